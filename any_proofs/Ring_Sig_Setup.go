@@ -1,7 +1,6 @@
 package any_proofs
 
 import (
-	"crypto/sha256"
 	"errors"
 	"math/big"
 
@@ -55,6 +54,32 @@ func (prover *Prover) Generate_b_0(k int, N int) ([]*big.Int, error) {
 	return b_0, nil
 }
 
+//Generete vector b_0 selecting the ring positions of the given secret keys, together with
+//the secret key of every position (zero where b_0 is zero)
+func Generate_Selected_b_0(ck utils.Point, ring []utils.Point, secrets []*big.Int) ([]*big.Int, []*big.Int, error) {
+	b_0 := Generate_Scalar_Vector(big.NewInt(0), len(ring))
+	keys := Generate_Scalar_Vector(big.NewInt(0), len(ring))
+	for _, secret := range secrets {
+		if !utils.Is_Valid_Scalar(secret) || secret.Sign() == 0 {
+			return nil, nil, errors.New("the secret keys should be non-zero elements of Zp")
+		}
+		key := utils.Commit(ck, secret)
+		found := false
+		for i := range ring {
+			if b_0[i].Sign() == 0 && utils.Is_Equal_Point(ring[i], key) {
+				b_0[i] = big.NewInt(1)
+				keys[i] = secret
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, nil, errors.New("a secret key does not belong to the ring set")
+		}
+	}
+	return b_0, keys, nil
+}
+
 //Generete vector b_1 according to b_0
 func Generate_b_1(b_0 []*big.Int) (b_1 []*big.Int) {
 	for i := 0; i < len(b_0); i++ {
@@ -132,21 +157,3 @@ func (prover *Prover) Generate_Multi_Public_Key(ck utils.Point, k, N int, bit []
 	}
 	return public_key
 }
-
-// Generate challenge
-func Generate_YZ(A utils.Point, B utils.Point) (*big.Int, *big.Int) {
-	AB1 := utils.Cal_Point_Add(A, utils.Cal_Point_Sca(B, big.NewInt(1)))
-	AB2 := utils.Cal_Point_Add(A, utils.Cal_Point_Sca(B, big.NewInt(2)))
-	y32 := sha256.Sum256(AB1.Point2Bytes())
-	y := big.NewInt(0).SetBytes(y32[:])
-	z32 := sha256.Sum256(AB2.Point2Bytes())
-	z := big.NewInt(0).SetBytes(z32[:])
-	return y, z
-}
-
-func Generate_X(T1 utils.Point, T2 utils.Point, E utils.Point) *big.Int {
-	T1T2E := utils.Cal_Point_Add(E, utils.Cal_Point_Add(T1, T2))
-	x32 := sha256.Sum256(T1T2E.Point2Bytes())
-	x := big.NewInt(0).SetBytes(x32[:])
-	return x
-}
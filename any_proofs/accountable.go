@@ -0,0 +1,203 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/sigma"
+	"anyOutOfMany/utils"
+)
+
+// Public statement of an accountable proof: the ring and the public key Q = ck^x of the designated opener
+type AccountableStatement struct {
+	Statement
+	Opener_Key utils.Point // opener public key Q
+}
+
+// Accountable proof: the selector b_0 encrypted bit by bit under Q, a ring proof of <b_0, w> = omega committed in W
+// for a challenge vector w, a proof that W and \prod_i D_i^{w_i} hide the same omega, and proofs that every
+// ciphertext is well formed
+type AccountableProof struct {
+	Proof
+	Cipher_R, Cipher_D []utils.Point       // ElGamal ciphertexts R_i = ck^{rho_i}, D_i = v^{b0_i} Q^{rho_i}
+	Pub_Weight         utils.Point         // W = v^{<b_0, w>} u^gamma
+	Equality           sigma.EqualityProof // W under (v, u) and \prod_i D_i^{w_i} under (v, Q) commit to the same value
+	Bits               []sigma.BitProof    // (R_i, D_i) encrypts a bit under Q
+}
+
+// Opener's proof of correct decryption: a Chaum-Pedersen proof of log_ck Q = log_{R_i} (D_i v^{-b0_i}) for all i
+type Opening struct {
+	T_0 utils.Point   // T_0 = ck^a
+	T   []utils.Point // T_i = R_i^a
+	S   *big.Int      // s = a + c x
+}
+
+// Generate an accountable proof for the given secret keys of the ring, which ordinary verifiers check without
+// learning the signers, while the opener can reveal them
+func (params *Params) AccountableProve(ring []utils.Point, secrets []*big.Int, opener_key utils.Point) (AccountableStatement, AccountableProof, error) {
+	if err := params.checkRing(ring); err != nil {
+		return AccountableStatement{}, AccountableProof{}, err
+	}
+	if !utils.Is_Valid_Point(opener_key) {
+		return AccountableStatement{}, AccountableProof{}, errors.New("the opener key should be a point on the curve")
+	}
+	b_0, keys, err := Generate_Selected_b_0(params.Public_ck, ring, secrets)
+	if err != nil {
+		return AccountableStatement{}, AccountableProof{}, err
+	}
+	N := len(ring)
+	statement := AccountableStatement{Statement: params.Statement(ring), Opener_Key: opener_key}
+
+	// Encrypt the selector bits
+	rho := hedgedNonces(statement.Statement.newTranscript("accountable"), b_0, keys).Scalar_Vector(N)
+	var Cipher_R, Cipher_D []utils.Point
+	for i := 0; i < N; i++ {
+		Cipher_R = append(Cipher_R, utils.Commit(params.Public_ck, rho[i]))
+		Cipher_D = append(Cipher_D, utils.Pedersen_Commit(params.Gen_v, opener_key, b_0[i], rho[i]))
+	}
+	return statement, statement.prove(b_0, keys, rho, Cipher_R, Cipher_D), nil
+}
+
+// Verify an accountable proof
+func AccountableVerify(statement AccountableStatement, proof AccountableProof) error {
+	N := len(statement.Pub_Vec_Key)
+	if len(proof.Cipher_R) != N || len(proof.Cipher_D) != N {
+		return errors.New("every ring position should have one ciphertext")
+	}
+	for _, point := range append(append([]utils.Point{statement.Opener_Key}, proof.Cipher_R...), proof.Cipher_D...) {
+		if !utils.Is_Valid_Point(point) {
+			return errors.New("the proof contains an invalid point")
+		}
+	}
+	fs := statement.newTranscript(proof.Cipher_R, proof.Cipher_D)
+	w := Generate_Exp_Scalar_Vector(fs.Challenge("w"), N)
+	fs.Append_Point("W", proof.Pub_Weight)
+	if err := verifyConstrained(fs, statement.Statement, w, big.NewInt(0), &proof.Pub_Weight, proof.Proof); err != nil {
+		return errors.New("invalid accountable proof")
+	}
+	D_w := utils.Multi_Scalar_Mult(proof.Cipher_D, w)
+	if err := sigma.VerifyEquality(fs, statement.Gen_v, statement.Gen_u, proof.Pub_Weight, statement.Gen_v, statement.Opener_Key, D_w, proof.Equality); err != nil {
+		return errors.New("invalid accountable proof")
+	}
+	if len(proof.Bits) != N {
+		return errors.New("every ciphertext should have one bit proof")
+	}
+	for i := 0; i < N; i++ {
+		if err := sigma.VerifyBit(fs, statement.Public_ck, statement.Opener_Key, statement.Gen_v, proof.Cipher_R[i], proof.Cipher_D[i], proof.Bits[i]); err != nil {
+			return errors.New("a ciphertext does not encrypt a bit")
+		}
+	}
+	return nil
+}
+
+// Decrypt the selector of an accountable proof with the opener secret x, returning the signer indices
+// and a proof of correct opening
+func Open(statement AccountableStatement, proof AccountableProof, openerSecret *big.Int) ([]int, Opening, error) {
+	N := len(statement.Pub_Vec_Key)
+	if len(proof.Cipher_R) != N || len(proof.Cipher_D) != N {
+		return nil, Opening{}, errors.New("every ring position should have one ciphertext")
+	}
+	if !utils.Is_Equal_Point(utils.Commit(statement.Public_ck, openerSecret), statement.Opener_Key) {
+		return nil, Opening{}, errors.New("the opener secret does not match the opener key")
+	}
+
+	// D_i R_i^{-x} = v^{b0_i}
+	var indices []int
+	neg_x := utils.Neg_Zp(openerSecret)
+	for i := 0; i < N; i++ {
+		M := utils.Multi_Scalar_Mult([]utils.Point{proof.Cipher_D[i], proof.Cipher_R[i]}, []*big.Int{big.NewInt(1), neg_x})
+		if utils.Is_Equal_Point(M, statement.Gen_v) {
+			indices = append(indices, i)
+		} else if !utils.Is_Identity_Point(M) {
+			return nil, Opening{}, errors.New("a ciphertext does not encrypt a bit")
+		}
+	}
+
+	a := hedgedNonces(statement.newTranscript(proof.Cipher_R, proof.Cipher_D), []*big.Int{openerSecret}).Scalar()
+	opening := Opening{T_0: utils.Commit(statement.Public_ck, a)}
+	for i := 0; i < N; i++ {
+		opening.T = append(opening.T, utils.Commit(proof.Cipher_R[i], a))
+	}
+	c := statement.openingChallenge(proof, indices, opening)
+	opening.S = utils.Add_In_P(a, utils.Mul_In_P(c, openerSecret))
+	return indices, opening, nil
+}
+
+// Verify that the signer indices are the correct decryption of the selector of an accountable proof
+func VerifyOpen(statement AccountableStatement, proof AccountableProof, indices []int, opening Opening) error {
+	N := len(statement.Pub_Vec_Key)
+	if len(proof.Cipher_R) != N || len(proof.Cipher_D) != N || len(opening.T) != N || !utils.Is_Valid_Scalar(opening.S) {
+		return errors.New("the opening is malformed")
+	}
+	b_0 := Generate_Scalar_Vector(big.NewInt(0), N)
+	for j, i := range indices {
+		if i < 0 || i >= N || (j > 0 && indices[j-1] >= i) {
+			return errors.New("the signer indices should be sorted ring positions")
+		}
+		b_0[i] = big.NewInt(1)
+	}
+	c := statement.openingChallenge(proof, indices, opening)
+	neg_c := utils.Neg_Zp(c)
+	neg_1 := utils.Neg_Zp(big.NewInt(1))
+
+	// ck^s Q^{-c} T_0^{-1} = 1 and R_i^s (D_i v^{-b0_i})^{-c} T_i^{-1} = 1, combined with random weights
+	var me utils.Multi_Exp
+	me.Add_Terms([]utils.Point{statement.Public_ck, statement.Opener_Key, opening.T_0}, []*big.Int{opening.S, neg_c, neg_1})
+	for i := 0; i < N; i++ {
+		r := utils.Generate_Random_Zp(q)
+		me.Add_Term(proof.Cipher_R[i], utils.Mul_In_P(r, opening.S))
+		me.Add_Term(proof.Cipher_D[i], utils.Mul_In_P(r, neg_c))
+		me.Add_Term(statement.Gen_v, utils.Mul_In_P(r, utils.Mul_In_P(c, b_0[i])))
+		me.Add_Term(opening.T[i], utils.Neg_Zp(r))
+	}
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("invalid opening")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// Prove over the ciphertexts (R_i, D_i) of the selector b_0, where D_i uses the randomness rho_i
+func (statement *AccountableStatement) prove(b_0 []*big.Int, keys []*big.Int, rho []*big.Int, Cipher_R []utils.Point, Cipher_D []utils.Point) AccountableProof {
+	N := len(b_0)
+	proof := AccountableProof{Cipher_R: Cipher_R, Cipher_D: Cipher_D}
+
+	// Bind the ciphertexts and derive the constraint vector w
+	fs := statement.newTranscript(Cipher_R, Cipher_D)
+	w := Generate_Exp_Scalar_Vector(fs.Challenge("w"), N)
+	omega := utils.Cal_IP_Vec(b_0, w)
+	gamma := hedgedNonces(fs, b_0, keys, rho).Scalar()
+	proof.Pub_Weight = utils.Pedersen_Commit(statement.Gen_v, statement.Gen_u, omega, gamma)
+	fs.Append_Point("W", proof.Pub_Weight)
+
+	proof.Proof = proveConstrained(fs, statement.Statement, b_0, keys, w, gamma)
+	D_w := utils.Multi_Scalar_Mult(Cipher_D, w)
+	proof.Equality = sigma.ProveEquality(fs, statement.Gen_v, statement.Gen_u, proof.Pub_Weight, gamma, statement.Gen_v, statement.Opener_Key, D_w, utils.Cal_IP_Vec(rho, w), omega)
+	for i := 0; i < N; i++ {
+		proof.Bits = append(proof.Bits, sigma.ProveBit(fs, statement.Public_ck, statement.Opener_Key, statement.Gen_v, Cipher_R[i], Cipher_D[i], b_0[i], rho[i]))
+	}
+	return proof
+}
+
+// Start the transcript of an accountable statement, binding the opener key and the ciphertexts
+func (statement *AccountableStatement) newTranscript(Cipher_R []utils.Point, Cipher_D []utils.Point) *utils.Fiat_Shamir {
+	fs := statement.Statement.newTranscript("accountable")
+	fs.Append_Point("Q", statement.Opener_Key)
+	fs.Append_Points("R", Cipher_R)
+	fs.Append_Points("D", Cipher_D)
+	return fs
+}
+
+// Challenge of the opening proof, binding the ciphertexts and the claimed indices
+func (statement *AccountableStatement) openingChallenge(proof AccountableProof, indices []int, opening Opening) *big.Int {
+	fs := statement.newTranscript(proof.Cipher_R, proof.Cipher_D)
+	fs.Append_Bytes("phase", []byte("open"))
+	fs.Append_Int("signers", len(indices))
+	for _, i := range indices {
+		fs.Append_Int("index", i)
+	}
+	fs.Append_Point("T_0", opening.T_0)
+	fs.Append_Points("T", opening.T)
+	return fs.Challenge("c")
+}
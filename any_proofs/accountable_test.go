@@ -0,0 +1,74 @@
+package any_proofs
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Ring of N keys of which the positions in signers are known, with an opener key pair
+func testRing(N int, signers []int) (Params, []utils.Point, []*big.Int, utils.Point, *big.Int) {
+	utils.Curve = secp256k1.S256()
+	var params Params
+	params.Setup(N)
+	ring := make([]utils.Point, N)
+	for i := range ring {
+		ring[i] = utils.Commit(params.Public_ck, randomScalar())
+	}
+	var secrets []*big.Int
+	for _, i := range signers {
+		sk := randomScalar()
+		ring[i] = utils.Commit(params.Public_ck, sk)
+		secrets = append(secrets, sk)
+	}
+	x := randomScalar()
+	return params, ring, secrets, utils.Commit(params.Public_ck, x), x
+}
+
+func TestAccountableOpen(t *testing.T) {
+	params, ring, secrets, Q, x := testRing(8, []int{2, 5})
+	statement, proof, err := params.AccountableProve(ring, secrets, Q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AccountableVerify(statement, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+	indices, opening, err := Open(statement, proof, x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 2 || indices[0] != 2 || indices[1] != 5 {
+		t.Fatalf("opened signers %v, want [2 5]", indices)
+	}
+	if err := VerifyOpen(statement, proof, indices, opening); err != nil {
+		t.Fatalf("honest opening rejected: %v", err)
+	}
+}
+
+// A prover who replaces R_i after encrypting D_i still answers the ring and equality proofs, which only use D,
+// but cannot prove that (R_i, D_i) encrypts a bit
+func TestAccountableTamperedR(t *testing.T) {
+	params, ring, secrets, Q, _ := testRing(8, []int{3})
+	statement := AccountableStatement{Statement: params.Statement(ring), Opener_Key: Q}
+	b_0, keys, err := Generate_Selected_b_0(params.Public_ck, ring, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rho []*big.Int
+	var Cipher_R, Cipher_D []utils.Point
+	for i := range ring {
+		rho = append(rho, randomScalar())
+		Cipher_R = append(Cipher_R, utils.Commit(params.Public_ck, rho[i]))
+		Cipher_D = append(Cipher_D, utils.Pedersen_Commit(params.Gen_v, Q, b_0[i], rho[i]))
+	}
+	Cipher_R[3] = utils.Commit(params.Public_ck, randomScalar())
+
+	proof := statement.prove(b_0, keys, rho, Cipher_R, Cipher_D)
+	if err := AccountableVerify(statement, proof); err == nil {
+		t.Fatal("proof with a tampered R_i accepted")
+	}
+}
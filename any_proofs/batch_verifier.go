@@ -0,0 +1,137 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Public statement of an any-out-of-many proof: generators and the ring set
+type Statement struct {
+	Public_ck            utils.Point   // generator as commitment key for public keys
+	Gen_u, Gen_v         utils.Point   // generators u,v
+	Gen_Vec_G, Gen_Vec_H []utils.Point // generator vector g h, at least N long
+	Pub_Vec_Key          []utils.Point // public key vector i.e., ring set
+}
+
+// Non-interactive any-out-of-many proof, the challenges are recomputed by the verifier
+type Proof struct {
+	A, B, T1, T2, E utils.Point
+	Tau_x, Mu, Ip   *big.Int
+	F_s             *big.Int
+	L, R            []utils.Point // inner-product argument rounds
+	C_zeta, C_eta   *big.Int      // compressed openings zeta, eta
+}
+
+// Error returned by BatchVerify, listing the indices of the invalid proofs
+type BatchError = utils.Batch_Error
+
+// Verify a single any-out-of-many proof
+func Verify(statement Statement, proof Proof) error {
+	if err := BatchVerify([]Statement{statement}, []Proof{proof}); err != nil {
+		return errors.New("invalid any-out-of-many proof")
+	}
+	return nil
+}
+
+// Verify many any-out-of-many proofs with one randomized multi-exponentiation over the shared generators,
+// when the batch fails the invalid proofs are located by bisection
+func BatchVerify(statements []Statement, proofs []Proof) error {
+	if len(statements) != len(proofs) {
+		return errors.New("the number of statements and proofs should be equal")
+	}
+	return utils.Batch_Verify("any-out-of-many proofs", len(proofs), func(me *utils.Multi_Exp, i int) error {
+		return addVerifyTerms(me, statements[i], proofs[i])
+	})
+}
+
+////////////////////////Private functions
+
+// Check that a statement and proof are well formed
+func checkProofFormat(statement Statement, proof Proof) error {
+	N := len(statement.Pub_Vec_Key)
+	if N == 0 || len(statement.Gen_Vec_G) < N || len(statement.Gen_Vec_H) < N {
+		return errors.New("the generator vectors should be at least as long as the ring set")
+	}
+	points := []utils.Point{statement.Public_ck, statement.Gen_u, statement.Gen_v, proof.A, proof.B, proof.T1, proof.T2, proof.E}
+	points = append(points, statement.Pub_Vec_Key...)
+	points = append(points, statement.Gen_Vec_G[:N]...)
+	points = append(points, statement.Gen_Vec_H[:N]...)
+	points = append(points, proof.L...)
+	points = append(points, proof.R...)
+	for _, point := range points {
+		if !utils.Is_Valid_Point(point) {
+			return errors.New("the proof contains an invalid point")
+		}
+	}
+	for _, scalar := range []*big.Int{proof.Tau_x, proof.Mu, proof.Ip, proof.F_s, proof.C_zeta, proof.C_eta} {
+		if !utils.Is_Valid_Scalar(scalar) {
+			return errors.New("the proof contains an invalid scalar")
+		}
+	}
+	return nil
+}
+
+// Add the two verification equations of one proof, each weighted by a fresh random value:
+// (1) v^{ip} u^{tau_x} = v^{delta} T1^x T2^{x^2}
+// (2) (g \circ P^{y^N})^{a s} (h^{y^{-N}})^{b s'} v^{x_ip (ab - ip)} L^{-x} R^{-x^{-1}} = A B^x g^z h^z u^{-mu} ck^{f_s} E^x P^{z y^N}
+func addVerifyTerms(me *utils.Multi_Exp, statement Statement, proof Proof) error {
+	if err := checkProofFormat(statement, proof); err != nil {
+		return err
+	}
+	N := len(statement.Pub_Vec_Key)
+
+	// Recompute the challenges
+	fs := statement.newTranscript("plain")
+	fs.Append_Point("A", proof.A)
+	fs.Append_Point("B", proof.B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+	fs.Append_Point("T1", proof.T1)
+	fs.Append_Point("T2", proof.T2)
+	fs.Append_Point("E", proof.E)
+	x := fs.Challenge("x")
+	x_ip, x_j, inv_x_j, s, inv_s, ok := utils.Opt_Challenges(fs, proof.Ip, proof.L, proof.R, N)
+	if !ok {
+		return errors.New("the inner-product argument has a wrong number of rounds")
+	}
+	YN := Generate_Exp_Scalar_Vector(y, N)
+	Inv_YN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), N)
+	x2 := utils.Mul_In_P(x, x)
+
+	// delta = <z \cdot 1^N \circ y^N, (z+1) \cdot 1^N>
+	z1N := Generate_Scalar_Vector(z, N)
+	z1N_1N := utils.Cal_Add_Vec(z1N, Generate_Scalar_Vector(big.NewInt(1), N))
+	delta := utils.Cal_IP_Vec(utils.Cal_HP_Vec(z1N, YN), z1N_1N)
+
+	// Random weights of the two equations
+	r_1 := utils.Generate_Random_Zp(q)
+	r_2 := utils.Generate_Random_Zp(q)
+	neg_r_2 := utils.Neg_Zp(r_2)
+
+	// Equation (1)
+	ab := utils.Mul_In_P(proof.C_zeta, proof.C_eta)
+	v_exp := utils.Add_In_P(utils.Mul_In_P(r_1, utils.Sub_In_P(proof.Ip, delta)), utils.Mul_In_P(r_2, utils.Mul_In_P(x_ip, utils.Sub_In_P(ab, proof.Ip))))
+	u_exp := utils.Add_In_P(utils.Mul_In_P(r_1, proof.Tau_x), utils.Mul_In_P(r_2, proof.Mu))
+	me.Add_Term(statement.Gen_v, v_exp)
+	me.Add_Term(statement.Gen_u, u_exp)
+	me.Add_Term(proof.T1, utils.Neg_Zp(utils.Mul_In_P(r_1, x)))
+	me.Add_Term(proof.T2, utils.Neg_Zp(utils.Mul_In_P(r_1, x2)))
+
+	// Equation (2)
+	for i := 0; i < N; i++ {
+		as_z := utils.Sub_In_P(utils.Mul_In_P(proof.C_zeta, s[i]), z)
+		bs_z := utils.Sub_In_P(utils.Mul_In_P(utils.Mul_In_P(proof.C_eta, inv_s[i]), Inv_YN[i]), z)
+		me.Add_Term(statement.Gen_Vec_G[i], utils.Mul_In_P(r_2, as_z))
+		me.Add_Term(statement.Pub_Vec_Key[i], utils.Mul_In_P(r_2, utils.Mul_In_P(YN[i], as_z)))
+		me.Add_Term(statement.Gen_Vec_H[i], utils.Mul_In_P(r_2, bs_z))
+	}
+	me.Add_Term(statement.Public_ck, utils.Mul_In_P(neg_r_2, proof.F_s))
+	me.Add_Term(proof.A, neg_r_2)
+	me.Add_Term(proof.B, utils.Mul_In_P(neg_r_2, x))
+	me.Add_Term(proof.E, utils.Mul_In_P(neg_r_2, x))
+	me.Add_Terms(proof.L, utils.Cal_Sca_Vec(x_j, neg_r_2))
+	me.Add_Terms(proof.R, utils.Cal_Sca_Vec(inv_x_j, neg_r_2))
+	return nil
+}
@@ -0,0 +1,44 @@
+package any_proofs
+
+import (
+	"testing"
+
+	"anyOutOfMany/utils"
+)
+
+// A proof of the statement S1 is rejected for every statement S2 differing in one field, and in another mode
+func TestReplay(t *testing.T) {
+	params, _, _, _, _ := testRing(8, nil)
+	var prover Prover
+	prover.New(params.Public_ck, utils.GeneratePoint(), params.Gen_u, params.Gen_v, params.Gen_Vec_G[:8], params.Gen_Vec_H[:8], 2, 8)
+	statement, proof := prover.GenerateProof()
+	if err := Verify(statement, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+
+	mutations := map[string]func(statement *Statement){
+		"ck":   func(statement *Statement) { statement.Public_ck = utils.GeneratePoint() },
+		"u":    func(statement *Statement) { statement.Gen_u = utils.GeneratePoint() },
+		"v":    func(statement *Statement) { statement.Gen_v = utils.GeneratePoint() },
+		"G":    func(statement *Statement) { statement.Gen_Vec_G = replacePoint(statement.Gen_Vec_G, 7) },
+		"H":    func(statement *Statement) { statement.Gen_Vec_H = replacePoint(statement.Gen_Vec_H, 0) },
+		"ring": func(statement *Statement) { statement.Pub_Vec_Key = replacePoint(statement.Pub_Vec_Key, 3) },
+	}
+	for field, mutate := range mutations {
+		replayed := statement
+		mutate(&replayed)
+		if err := Verify(replayed, proof); err == nil {
+			t.Errorf("proof replayed against another %s accepted", field)
+		}
+	}
+
+	// A proof with the transcript of another mode is rejected
+	params, ring, secrets, _, _ := testRing(8, []int{0, 5})
+	count, counted, err := params.ExactCountProve(ring, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(count.Statement, counted); err == nil {
+		t.Error("exact-count proof accepted as a plain proof")
+	}
+}
@@ -0,0 +1,101 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Public statement of a ring of BIP340 x-only public keys under the standard generator G
+type BIP340Statement struct {
+	Gen_u, Gen_v         utils.Point   // generators u,v
+	Gen_Vec_G, Gen_Vec_H []utils.Point // generator vector g h, at least N long
+	X_Only               [][32]byte    // ring of x-only public keys
+}
+
+// Generate an any-out-of-many proof over x-only public keys, e.g. Taproot output keys, for the given secret keys.
+// Every key is lifted to the point with even Y, and a secret key whose point d G has odd Y is negated
+func (params *Params) BIP340Prove(ring [][32]byte, secrets []*big.Int) (BIP340Statement, Proof, error) {
+	statement := BIP340Statement{
+		Gen_u:     params.Gen_u,
+		Gen_v:     params.Gen_v,
+		Gen_Vec_G: params.Gen_Vec_G,
+		Gen_Vec_H: params.Gen_Vec_H,
+		X_Only:    ring,
+	}
+	lifted, err := statement.lift()
+	if err != nil {
+		return BIP340Statement{}, Proof{}, err
+	}
+	if err := params.checkRing(lifted.Pub_Vec_Key); err != nil {
+		return BIP340Statement{}, Proof{}, err
+	}
+	var normalized []*big.Int
+	for _, secret := range secrets {
+		if !utils.Is_Valid_Scalar(secret) || secret.Sign() == 0 {
+			return BIP340Statement{}, Proof{}, errors.New("the secret keys should be non-zero elements of Zp")
+		}
+		normalized = append(normalized, Normalize_BIP340_Secret(secret))
+	}
+	b_0, keys, err := Generate_Selected_b_0(lifted.Public_ck, lifted.Pub_Vec_Key, normalized)
+	if err != nil {
+		return BIP340Statement{}, Proof{}, err
+	}
+	N := len(ring)
+	proof := proveConstrained(lifted.newTranscript("bip340"), lifted, b_0, keys, Generate_Scalar_Vector(big.NewInt(0), N), big.NewInt(0))
+	return statement, proof, nil
+}
+
+// Verify an any-out-of-many proof over x-only public keys
+func BIP340Verify(statement BIP340Statement, proof Proof) error {
+	lifted, err := statement.lift()
+	if err != nil {
+		return err
+	}
+	N := len(statement.X_Only)
+	if err := verifyConstrained(lifted.newTranscript("bip340"), lifted, Generate_Scalar_Vector(big.NewInt(0), N), big.NewInt(0), nil, proof); err != nil {
+		return errors.New("invalid any-out-of-many proof over x-only keys")
+	}
+	return nil
+}
+
+// Negate a secret key d if d G has odd Y, so that it matches the even-Y lifting of its x-only public key
+func Normalize_BIP340_Secret(secret *big.Int) *big.Int {
+	P := utils.Commit(utils.Base_Point(), secret)
+	if P.Y.Bit(0) == 1 {
+		return utils.Neg_Zp(secret)
+	}
+	return secret
+}
+
+// Serialize the x-only public key of a secret key
+func BIP340_Public_Key(secret *big.Int) [32]byte {
+	var x [32]byte
+	utils.Commit(utils.Base_Point(), secret).X.FillBytes(x[:])
+	return x
+}
+
+////////////////////////Private functions
+
+// Lift the x-only keys into a ring statement with commitment key G
+func (statement *BIP340Statement) lift() (Statement, error) {
+	if len(statement.X_Only) == 0 {
+		return Statement{}, errors.New("the ring set should not be empty")
+	}
+	lifted := Statement{
+		Public_ck: utils.Base_Point(),
+		Gen_u:     statement.Gen_u,
+		Gen_v:     statement.Gen_v,
+		Gen_Vec_G: statement.Gen_Vec_G,
+		Gen_Vec_H: statement.Gen_Vec_H,
+	}
+	for i := range statement.X_Only {
+		P, ok := utils.Lift_X(statement.X_Only[i][:])
+		if !ok {
+			return Statement{}, errors.New("an x-only public key is not on the curve")
+		}
+		lifted.Pub_Vec_Key = append(lifted.Pub_Vec_Key, P)
+	}
+	return lifted, nil
+}
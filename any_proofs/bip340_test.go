@@ -0,0 +1,111 @@
+package any_proofs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Secret keys and x-only public keys of the BIP340 test vectors 0-3
+var bip340Vectors = []struct {
+	secret string
+	public string
+}{
+	{"0000000000000000000000000000000000000000000000000000000000000003", "F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9"},
+	{"B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF", "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659"},
+	{"C90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B14E5C9", "DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EB8"},
+	{"0B432B2677937381AEF05BB02A66ECD012773062CF3FA2549E44F58ED2401710", "25D1DFF95105F5253C4022F628A996AD3A0D95FBF21D468A1B33F8C160D8F517"},
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestBIP340Vectors(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	for i, vector := range bip340Vectors {
+		secret := new(big.Int).SetBytes(decodeHex(t, vector.secret))
+		public := decodeHex(t, vector.public)
+		key := BIP340_Public_Key(secret)
+		if !bytes.Equal(key[:], public) {
+			t.Errorf("vector %d: public key %X, want %s", i, key, vector.public)
+		}
+
+		// The lifted point has even Y and is opened by the normalized secret
+		P, ok := utils.Lift_X(public)
+		if !ok || P.Y.Bit(0) != 0 {
+			t.Fatalf("vector %d: lift_x failed or returned odd Y", i)
+		}
+		if !utils.Is_Equal_Point(utils.Commit(utils.Base_Point(), Normalize_BIP340_Secret(secret)), P) {
+			t.Errorf("vector %d: the normalized secret does not open lift_x(pk)", i)
+		}
+
+		// Cross-check with the library: the compressed key is 0x02 || x exactly for even Y
+		compressed := secp256k1.PrivKeyFromBytes(secret.Bytes()).PubKey().SerializeCompressed()
+		if !bytes.Equal(compressed[1:], public) {
+			t.Errorf("vector %d: x does not match the library", i)
+		}
+		negated := Normalize_BIP340_Secret(secret).Cmp(secret) != 0
+		if negated != (compressed[0] == 0x03) {
+			t.Errorf("vector %d: secret negated = %v for prefix %x", i, negated, compressed[0])
+		}
+	}
+
+	// lift_x fails for an x with no point (BIP340 vector 5) and for x >= p (vector 14)
+	for _, x := range []string{
+		"EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30",
+	} {
+		if _, ok := utils.Lift_X(decodeHex(t, x)); ok {
+			t.Errorf("lift_x(%s) succeeded", x)
+		}
+	}
+}
+
+// A ring whose signers' points d G have even and odd Y
+func TestBIP340MixedParity(t *testing.T) {
+	params, _, _, _, _ := testRing(8, nil)
+	var ring [][32]byte
+	var secrets []*big.Int
+	parities := map[uint]bool{}
+	for len(ring) < 8 {
+		secret := randomScalar()
+		ring = append(ring, BIP340_Public_Key(secret))
+		if parity := utils.Commit(utils.Base_Point(), secret).Y.Bit(0); len(secrets) < 2 && !parities[parity] {
+			parities[parity] = true
+			secrets = append(secrets, secret)
+		}
+	}
+	if len(secrets) != 2 {
+		t.Skip("no secrets of both parities sampled")
+	}
+	statement, proof, err := params.BIP340Prove(ring, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := BIP340Verify(statement, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+	statement.X_Only = append([][32]byte{}, ring...)
+	statement.X_Only[7] = BIP340_Public_Key(randomScalar())
+	if err := BIP340Verify(statement, proof); err == nil {
+		t.Fatal("proof accepted for another ring")
+	}
+
+	// An x-only key that is not on the curve is rejected
+	var off [32]byte
+	copy(off[:], decodeHex(t, "EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34"))
+	statement.X_Only[7] = off
+	if err := BIP340Verify(statement, proof); err == nil {
+		t.Fatal("ring with an x-only key off the curve accepted")
+	}
+}
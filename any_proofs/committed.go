@@ -0,0 +1,77 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Public statement of a ring of committed keys C_i = ck^{sk_i} h^{r_i}, Pub_Vec_Key holds the commitments
+type CommittedStatement struct {
+	Statement
+	Gen_Blind utils.Point // blinding generator h of the key commitments
+}
+
+// Proof of knowledge of openings of some ring commitments, F_s answers for the keys and F_r for the blindings
+type CommittedProof struct {
+	Proof
+	F_r *big.Int // f_r = r_r x + \sum y^i b0_i r_i
+}
+
+// Generate a proof of knowledge of the openings (sk_j, r_j) of some commitments of the ring, E carries a second
+// blinding h^{-r_r} and the proof a second response f_r
+func (params *Params) CommittedProve(ring []utils.Point, blind utils.Point, secrets []*big.Int, blindings []*big.Int) (CommittedStatement, CommittedProof, error) {
+	if err := params.checkRing(ring); err != nil {
+		return CommittedStatement{}, CommittedProof{}, err
+	}
+	if len(secrets) != len(blindings) {
+		return CommittedStatement{}, CommittedProof{}, errors.New("every secret key should have a blinding factor")
+	}
+	statement := CommittedStatement{Statement: params.Statement(ring), Gen_Blind: blind}
+	N := len(ring)
+	b_0 := Generate_Scalar_Vector(big.NewInt(0), N)
+	keys := Generate_Scalar_Vector(big.NewInt(0), N)
+	blinds := Generate_Scalar_Vector(big.NewInt(0), N)
+	for j := range secrets {
+		if !utils.Is_Valid_Scalar(secrets[j]) || !utils.Is_Valid_Scalar(blindings[j]) {
+			return CommittedStatement{}, CommittedProof{}, errors.New("the openings should be elements of Zp")
+		}
+		C := utils.Pedersen_Commit(params.Public_ck, blind, secrets[j], blindings[j])
+		found := false
+		for i := range ring {
+			if b_0[i].Sign() == 0 && utils.Is_Equal_Point(ring[i], C) {
+				b_0[i] = big.NewInt(1)
+				keys[i] = secrets[j]
+				blinds[i] = blindings[j]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return CommittedStatement{}, CommittedProof{}, errors.New("an opening does not belong to the ring set")
+		}
+	}
+	bases := []utils.Point{params.Public_ck, blind}
+	proof, f_extra := proveConstrainedBases(statement.newTranscript(), statement.Statement, bases, b_0, [][]*big.Int{keys, blinds}, Generate_Scalar_Vector(big.NewInt(0), N), big.NewInt(0))
+	return statement, CommittedProof{Proof: proof, F_r: f_extra[0]}, nil
+}
+
+// Verify a proof over a ring of committed keys
+func CommittedVerify(statement CommittedStatement, proof CommittedProof) error {
+	N := len(statement.Pub_Vec_Key)
+	bases := []utils.Point{statement.Public_ck, statement.Gen_Blind}
+	if err := verifyConstrainedBases(statement.newTranscript(), statement.Statement, bases, []*big.Int{proof.F_r}, Generate_Scalar_Vector(big.NewInt(0), N), big.NewInt(0), nil, proof.Proof); err != nil {
+		return errors.New("invalid committed ring proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// Start the transcript of a committed ring statement, binding the blinding generator
+func (statement *CommittedStatement) newTranscript() *utils.Fiat_Shamir {
+	fs := statement.Statement.newTranscript("committed")
+	fs.Append_Point("h", statement.Gen_Blind)
+	return fs
+}
@@ -0,0 +1,78 @@
+package any_proofs
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+)
+
+// Ring of N key commitments ck^{sk} h^{r} of which the positions in signers are opened
+func testCommittedRing(N int, signers []int) (Params, utils.Point, []utils.Point, []*big.Int, []*big.Int) {
+	params, _, _, _, _ := testRing(N, nil)
+	blind := utils.GeneratePoint()
+	ring := make([]utils.Point, N)
+	for i := range ring {
+		ring[i] = utils.GeneratePoint()
+	}
+	var secrets, blindings []*big.Int
+	for _, i := range signers {
+		sk, r := randomScalar(), randomScalar()
+		ring[i] = utils.Pedersen_Commit(params.Public_ck, blind, sk, r)
+		secrets = append(secrets, sk)
+		blindings = append(blindings, r)
+	}
+	return params, blind, ring, secrets, blindings
+}
+
+func TestCommitted(t *testing.T) {
+	one := big.NewInt(1)
+	for _, signers := range [][]int{{0}, {3, 4}, {1, 2, 5, 7}} {
+		params, blind, ring, secrets, blindings := testCommittedRing(8, signers)
+		statement, proof, err := params.CommittedProve(ring, blind, secrets, blindings)
+		if err != nil {
+			t.Fatalf("%v: %v", signers, err)
+		}
+		if err := CommittedVerify(statement, proof); err != nil {
+			t.Errorf("%v: honest proof rejected: %v", signers, err)
+		}
+
+		tests := []struct {
+			name   string
+			mutate func(statement *CommittedStatement, proof *CommittedProof)
+		}{
+			{"F_r + 1", func(_ *CommittedStatement, proof *CommittedProof) { proof.F_r = utils.Add_In_P(proof.F_r, one) }},
+			{"F_r swapped with F_s", func(_ *CommittedStatement, proof *CommittedProof) { proof.F_r, proof.F_s = proof.F_s, proof.F_r }},
+			{"F_r missing", func(_ *CommittedStatement, proof *CommittedProof) { proof.F_r = nil }},
+			{"F_r outside Zp", func(_ *CommittedStatement, proof *CommittedProof) { proof.F_r = utils.Curve.Params().N }},
+			{"other blinding generator", func(statement *CommittedStatement, _ *CommittedProof) { statement.Gen_Blind = utils.GeneratePoint() }},
+		}
+		for _, test := range tests {
+			mutated, mutated_proof := statement, proof
+			test.mutate(&mutated, &mutated_proof)
+			if CommittedVerify(mutated, mutated_proof) == nil {
+				t.Errorf("%v: proof accepted with %s", signers, test.name)
+			}
+		}
+	}
+}
+
+// Openings with a wrong key or blinding do not select any ring commitment
+func TestCommittedWrongOpening(t *testing.T) {
+	params, blind, ring, secrets, blindings := testCommittedRing(8, []int{2, 6})
+	tests := []struct {
+		name      string
+		secrets   []*big.Int
+		blindings []*big.Int
+	}{
+		{"wrong blinding", secrets, []*big.Int{blindings[0], randomScalar()}},
+		{"wrong key", []*big.Int{randomScalar(), secrets[1]}, blindings},
+		{"swapped blindings", secrets, []*big.Int{blindings[1], blindings[0]}},
+		{"missing blinding", secrets, blindings[:1]},
+	}
+	for _, test := range tests {
+		if _, _, err := params.CommittedProve(ring, blind, test.secrets, test.blindings); err == nil {
+			t.Errorf("%s: proof generated", test.name)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Any-out-of-many proof with the additional linear constraint <b_0, w> = omega + omega' on the selector vector,
+// where omega is public and omega' is committed in W = v^{omega'} u^gamma (or zero without W). The challenges
+// are derived from the transcript fs, which the caller has initialized with the statement including omega and W
+
+// Generate the proof for the selector b_0 and the secret keys at the selected positions (zero elsewhere)
+func proveConstrained(fs *utils.Fiat_Shamir, statement Statement, b_0 []*big.Int, keys []*big.Int, w []*big.Int, gamma *big.Int) Proof {
+	proof, _ := proveConstrainedBases(fs, statement, []utils.Point{statement.Public_ck}, b_0, [][]*big.Int{keys}, w, gamma)
+	return proof
+}
+
+// Generate the proof for ring members P_i = \sum_k keys[k]_i bases_k, with one blinding r_k of E and one response
+// f_k per base. F_s of the proof is the response of bases_0, the responses of the other bases are returned
+func proveConstrainedBases(fs *utils.Fiat_Shamir, statement Statement, bases []utils.Point, b_0 []*big.Int, keys [][]*big.Int, w []*big.Int, gamma *big.Int) (Proof, []*big.Int) {
+	return proveConstrainedBlinded(fs, statement, bases, nil, b_0, keys, w, gamma)
+}
+
+// Generate the proof for ring members over several bases with the given blindings r_k of E, or blindings drawn
+// with the other nonces if r_s is nil. The nonces are hedged with the witness and the statement absorbed by fs
+func proveConstrainedBlinded(fs *utils.Fiat_Shamir, statement Statement, bases []utils.Point, r_s []*big.Int, b_0 []*big.Int, keys [][]*big.Int, w []*big.Int, gamma *big.Int) (Proof, []*big.Int) {
+	N := len(statement.Pub_Vec_Key)
+	Vec_G := statement.Gen_Vec_G[:N]
+	Vec_H := statement.Gen_Vec_H[:N]
+	nonces := hedgedNonces(fs, append([][]*big.Int{b_0, {gamma}}, keys...)...)
+	if r_s == nil {
+		r_s = nonces.Scalar_Vector(len(bases))
+	}
+
+	//Generate commitments A, B
+	b_1 := Generate_b_1(b_0)
+	s_0 := nonces.Scalar_Vector(N)
+	s_1 := nonces.Scalar_Vector(N)
+	alpha := nonces.Scalar()
+	beta := nonces.Scalar()
+	A := utils.Cal_Point_Add(utils.Pedersen_Commit_Vector(Vec_G, Vec_H, b_0, b_1), utils.Commit(statement.Gen_u, alpha))
+	B := utils.Cal_Point_Add(utils.Pedersen_Commit_Vector(Vec_G, Vec_H, s_0, s_1), utils.Commit(statement.Gen_u, beta))
+
+	//Generate challenges y,z
+	fs.Append_Point("A", A)
+	fs.Append_Point("B", B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+
+	// l(x) = b0 + z1^N + s0 x, r(x) = y^N \circ (b1 + z1^N + s1 x) + z^2 w
+	YN := Generate_Exp_Scalar_Vector(y, N)
+	z1N := Generate_Scalar_Vector(z, N)
+	z2 := utils.Mul_In_P(z, z)
+	l_0 := utils.Cal_Add_Vec(b_0, z1N)
+	r_0 := utils.Cal_Add_Vec(utils.Cal_HP_Vec(YN, utils.Cal_Add_Vec(b_1, z1N)), utils.Cal_Sca_Vec(w, z2))
+	r_1 := utils.Cal_HP_Vec(YN, s_1)
+
+	//Compute T1, T2 with t1 = <l_0, r_1> + <s_0, r_0>, t2 = <s_0, r_1>
+	t_1 := utils.Add_In_P(utils.Cal_IP_Vec(l_0, r_1), utils.Cal_IP_Vec(s_0, r_0))
+	t_2 := utils.Cal_IP_Vec(s_0, r_1)
+	tau_1 := nonces.Scalar()
+	tau_2 := nonces.Scalar()
+	T1 := utils.Pedersen_Commit(statement.Gen_v, statement.Gen_u, t_1, tau_1)
+	T2 := utils.Pedersen_Commit(statement.Gen_v, statement.Gen_u, t_2, tau_2)
+
+	//Compute E = P^{y^N \circ s_0} \prod_k bases_k^{-r_k}
+	E := utils.Commit_Vector(statement.Pub_Vec_Key, utils.Cal_HP_Vec(YN, s_0))
+	for k := range bases {
+		E = utils.Cal_Point_Add(E, utils.Commit(bases[k], utils.Neg_Zp(r_s[k])))
+	}
+
+	//Generate challenge x
+	fs.Append_Point("T1", T1)
+	fs.Append_Point("T2", T2)
+	fs.Append_Point("E", E)
+	x := fs.Challenge("x")
+
+	//Compute zeta = l(x), eta = r(x) and t = <zeta, eta>
+	zeta := utils.Cal_Add_Vec(l_0, utils.Cal_Sca_Vec(s_0, x))
+	eta := utils.Cal_Add_Vec(r_0, utils.Cal_Sca_Vec(r_1, x))
+	ip := utils.Cal_IP_Vec(zeta, eta)
+
+	//Compute tau_x = tau_1 x + tau_2 x^2 + z^2 gamma, mu = alpha + beta x and f_k = r_k x + \sum y^i b0_i keys[k]_i
+	tau_x := utils.Add_In_P(utils.Mul_In_P(tau_1, x), utils.Mul_In_P(tau_2, utils.Mul_In_P(x, x)))
+	tau_x = utils.Add_In_P(tau_x, utils.Mul_In_P(z2, gamma))
+	mu := utils.Add_In_P(alpha, utils.Mul_In_P(beta, x))
+	var f_s []*big.Int
+	for k := range bases {
+		f_s = append(f_s, utils.Add_In_P(utils.Mul_In_P(r_s[k], x), utils.Cal_IP_Vec(utils.Cal_HP_Vec(YN, b_0), keys[k])))
+	}
+
+	// Compress the openings over generators g \circ P^{y^N} and h^{y^{-N}}
+	Inv_YN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), N)
+	Inv_Vec_H := utils.Generate_Point_Vector_with_y(Vec_H, Inv_YN)
+	Vec_G_P := utils.Cal_Point_Add_Vec(Vec_G, utils.Generate_Point_Vector_with_y(statement.Pub_Vec_Key, YN))
+	L, R, C_zeta, C_eta := utils.Opt_Prove(fs, zeta, eta, Vec_G_P, Inv_Vec_H, statement.Gen_v)
+
+	proof := Proof{
+		A:      A,
+		B:      B,
+		T1:     T1,
+		T2:     T2,
+		E:      E,
+		Tau_x:  tau_x,
+		Mu:     mu,
+		Ip:     ip,
+		F_s:    f_s[0],
+		L:      L,
+		R:      R,
+		C_zeta: C_zeta[0],
+		C_eta:  C_eta[0],
+	}
+	return proof, f_s[1:]
+}
+
+// Check the proof against the transcript fs in the same state as the prover's and return whether it is valid:
+// (1) v^{ip} u^{tau_x} = v^{delta} W^{z^2} T1^x T2^{x^2} with delta = (z+z^2) <1^N, y^N> + z^2 omega + z^3 <1^N, w>
+// (2) (g \circ P^{y^N})^{a s} (h^{y^{-N}})^{b s'} v^{x_ip (ab - ip)} L^{-x} R^{-x^{-1}} = A B^x g^z (h^{y^{-N}})^{z y^N + z^2 w} u^{-mu} ck^{f_s} E^x P^{z y^N}
+func verifyConstrained(fs *utils.Fiat_Shamir, statement Statement, w []*big.Int, omega *big.Int, W *utils.Point, proof Proof) error {
+	return verifyConstrainedBases(fs, statement, []utils.Point{statement.Public_ck}, nil, w, omega, W, proof)
+}
+
+// Check the proof for ring members over several bases, where ck^{f_s} in (2) becomes \prod_k bases_k^{f_k}
+// with f_0 = F_s and the other responses in f_extra
+func verifyConstrainedBases(fs *utils.Fiat_Shamir, statement Statement, bases []utils.Point, f_extra []*big.Int, w []*big.Int, omega *big.Int, W *utils.Point, proof Proof) error {
+	if err := checkProofFormat(statement, proof); err != nil {
+		return err
+	}
+	if len(f_extra)+1 != len(bases) {
+		return errors.New("every base should have one response")
+	}
+	for k := range f_extra {
+		if !utils.Is_Valid_Point(bases[k+1]) || !utils.Is_Valid_Scalar(f_extra[k]) {
+			return errors.New("the proof contains an invalid point or scalar")
+		}
+	}
+	if W != nil && !utils.Is_Valid_Point(*W) {
+		return errors.New("the proof contains an invalid point")
+	}
+	N := len(statement.Pub_Vec_Key)
+	if len(w) != N {
+		return errors.New("the constraint vector should be as long as the ring set")
+	}
+
+	// Recompute the challenges
+	fs.Append_Point("A", proof.A)
+	fs.Append_Point("B", proof.B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+	fs.Append_Point("T1", proof.T1)
+	fs.Append_Point("T2", proof.T2)
+	fs.Append_Point("E", proof.E)
+	x := fs.Challenge("x")
+	x_ip, x_j, inv_x_j, s, inv_s, ok := utils.Opt_Challenges(fs, proof.Ip, proof.L, proof.R, N)
+	if !ok {
+		return errors.New("the inner-product argument has a wrong number of rounds")
+	}
+	YN := Generate_Exp_Scalar_Vector(y, N)
+	Inv_YN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), N)
+	z2 := utils.Mul_In_P(z, z)
+	x2 := utils.Mul_In_P(x, x)
+	v1N := Generate_Scalar_Vector(big.NewInt(1), N)
+
+	delta := utils.Mul_In_P(utils.Add_In_P(z, z2), utils.Cal_IP_Vec(v1N, YN))
+	delta = utils.Add_In_P(delta, utils.Mul_In_P(z2, omega))
+	delta = utils.Add_In_P(delta, utils.Mul_In_P(utils.Mul_In_P(z2, z), utils.Cal_IP_Vec(v1N, w)))
+
+	// Random weights of the two equations
+	r_1 := utils.Generate_Random_Zp(q)
+	r_2 := utils.Generate_Random_Zp(q)
+	neg_r_2 := utils.Neg_Zp(r_2)
+
+	var me utils.Multi_Exp
+
+	// Equation (1)
+	ab := utils.Mul_In_P(proof.C_zeta, proof.C_eta)
+	v_exp := utils.Add_In_P(utils.Mul_In_P(r_1, utils.Sub_In_P(proof.Ip, delta)), utils.Mul_In_P(r_2, utils.Mul_In_P(x_ip, utils.Sub_In_P(ab, proof.Ip))))
+	u_exp := utils.Add_In_P(utils.Mul_In_P(r_1, proof.Tau_x), utils.Mul_In_P(r_2, proof.Mu))
+	me.Add_Term(statement.Gen_v, v_exp)
+	me.Add_Term(statement.Gen_u, u_exp)
+	if W != nil {
+		me.Add_Term(*W, utils.Neg_Zp(utils.Mul_In_P(r_1, z2)))
+	}
+	me.Add_Term(proof.T1, utils.Neg_Zp(utils.Mul_In_P(r_1, x)))
+	me.Add_Term(proof.T2, utils.Neg_Zp(utils.Mul_In_P(r_1, x2)))
+
+	// Equation (2)
+	for i := 0; i < N; i++ {
+		as_z := utils.Sub_In_P(utils.Mul_In_P(proof.C_zeta, s[i]), z)
+		bs := utils.Mul_In_P(utils.Mul_In_P(proof.C_eta, inv_s[i]), Inv_YN[i])
+		bs_z := utils.Sub_In_P(bs, utils.Add_In_P(z, utils.Mul_In_P(utils.Mul_In_P(z2, w[i]), Inv_YN[i])))
+		me.Add_Term(statement.Gen_Vec_G[i], utils.Mul_In_P(r_2, as_z))
+		me.Add_Term(statement.Pub_Vec_Key[i], utils.Mul_In_P(r_2, utils.Mul_In_P(YN[i], as_z)))
+		me.Add_Term(statement.Gen_Vec_H[i], utils.Mul_In_P(r_2, bs_z))
+	}
+	me.Add_Term(bases[0], utils.Mul_In_P(neg_r_2, proof.F_s))
+	for k := range f_extra {
+		me.Add_Term(bases[k+1], utils.Mul_In_P(neg_r_2, f_extra[k]))
+	}
+	me.Add_Term(proof.A, neg_r_2)
+	me.Add_Term(proof.B, utils.Mul_In_P(neg_r_2, x))
+	me.Add_Term(proof.E, utils.Mul_In_P(neg_r_2, x))
+	me.Add_Terms(proof.L, utils.Cal_Sca_Vec(x_j, neg_r_2))
+	me.Add_Terms(proof.R, utils.Cal_Sca_Vec(inv_x_j, neg_r_2))
+
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("invalid any-out-of-many proof")
+	}
+	return nil
+}
+
+// Hedged nonce source of a proof over the transcript fs for the secret witness vectors
+func hedgedNonces(fs *utils.Fiat_Shamir, witness ...[]*big.Int) *utils.Nonce_Source {
+	var secrets []*big.Int
+	for i := range witness {
+		secrets = append(secrets, witness[i]...)
+	}
+	return utils.Hedged_Nonce_Source(*fs, secrets)
+}
+
+// Start the Fiat-Shamir transcript of a ring statement for the given proof mode, binding the generators, the
+// first N generators of each vector and the ring set
+func (statement *Statement) newTranscript(mode string) *utils.Fiat_Shamir {
+	var fs utils.Fiat_Shamir
+	N := len(statement.Pub_Vec_Key)
+	fs.New("any_proofs")
+	fs.Append_Bytes("mode", []byte(mode))
+	fs.Append_Point("ck", statement.Public_ck)
+	fs.Append_Point("u", statement.Gen_u)
+	fs.Append_Point("v", statement.Gen_v)
+	fs.Append_Points("G", utils.Point_Prefix(statement.Gen_Vec_G, N))
+	fs.Append_Points("H", utils.Point_Prefix(statement.Gen_Vec_H, N))
+	fs.Append_Points("P", statement.Pub_Vec_Key)
+	return &fs
+}
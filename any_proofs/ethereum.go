@@ -0,0 +1,116 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Public statement of a ring of Ethereum accounts: the keys under the standard generator G and their addresses
+type EthereumStatement struct {
+	Statement
+	Addresses [][20]byte // address of every ring key
+}
+
+// Build a ring from 65-byte uncompressed public keys 0x04||X||Y. If addresses is not nil every key is checked
+// against its address, otherwise the addresses are derived from the keys
+func (params *Params) EthereumRing(keys [][]byte, addresses [][20]byte) (EthereumStatement, error) {
+	if addresses != nil && len(addresses) != len(keys) {
+		return EthereumStatement{}, errors.New("every key should have one address")
+	}
+	statement := EthereumStatement{Statement: params.Statement(nil)}
+	statement.Public_ck = utils.Base_Point()
+	for i := range keys {
+		P, err := Parse_Uncompressed_Key(keys[i])
+		if err != nil {
+			return EthereumStatement{}, err
+		}
+		address := Ethereum_Address(P)
+		if addresses != nil && address != addresses[i] {
+			return EthereumStatement{}, errors.New("a public key does not match its address")
+		}
+		statement.Pub_Vec_Key = append(statement.Pub_Vec_Key, P)
+		statement.Addresses = append(statement.Addresses, address)
+	}
+	if err := params.checkRing(statement.Pub_Vec_Key); err != nil {
+		return EthereumStatement{}, err
+	}
+	return statement, nil
+}
+
+// Generate an any-out-of-many proof over a ring of Ethereum accounts for the given secret keys
+func EthereumProve(statement EthereumStatement, secrets []*big.Int) (Proof, error) {
+	if err := statement.checkAddresses(); err != nil {
+		return Proof{}, err
+	}
+	b_0, keys, err := Generate_Selected_b_0(statement.Public_ck, statement.Pub_Vec_Key, secrets)
+	if err != nil {
+		return Proof{}, err
+	}
+	N := len(statement.Pub_Vec_Key)
+	return proveConstrained(statement.newTranscript(), statement.Statement, b_0, keys, Generate_Scalar_Vector(big.NewInt(0), N), big.NewInt(0)), nil
+}
+
+// Verify an any-out-of-many proof over a ring of Ethereum accounts, including that the keys match the address list
+func EthereumVerify(statement EthereumStatement, proof Proof) error {
+	if err := statement.checkAddresses(); err != nil {
+		return err
+	}
+	N := len(statement.Pub_Vec_Key)
+	if err := verifyConstrained(statement.newTranscript(), statement.Statement, Generate_Scalar_Vector(big.NewInt(0), N), big.NewInt(0), nil, proof); err != nil {
+		return errors.New("invalid any-out-of-many proof over Ethereum accounts")
+	}
+	return nil
+}
+
+// Parse a 65-byte uncompressed public key 0x04||X||Y
+func Parse_Uncompressed_Key(key []byte) (utils.Point, error) {
+	if len(key) != 65 || key[0] != 0x04 {
+		return utils.Point{}, errors.New("an uncompressed public key should be 65 bytes starting with 0x04")
+	}
+	P := utils.Point{X: new(big.Int).SetBytes(key[1:33]), Y: new(big.Int).SetBytes(key[33:])}
+	if !utils.Is_Valid_Point(P) {
+		return utils.Point{}, errors.New("the public key is not on the curve")
+	}
+	return P, nil
+}
+
+// Ethereum address of a public key: the last 20 bytes of Keccak-256(X||Y)
+func Ethereum_Address(P utils.Point) [20]byte {
+	var xy [64]byte
+	P.X.FillBytes(xy[:32])
+	P.Y.FillBytes(xy[32:])
+	hash := utils.Keccak256(xy[:])
+	var address [20]byte
+	copy(address[:], hash[12:])
+	return address
+}
+
+////////////////////////Private functions
+
+// Check that the keys use the standard generator and match the address list
+func (statement *EthereumStatement) checkAddresses() error {
+	if !utils.Is_Equal_Point(statement.Public_ck, utils.Base_Point()) {
+		return errors.New("the ring keys should be under the standard generator")
+	}
+	if len(statement.Addresses) != len(statement.Pub_Vec_Key) {
+		return errors.New("every key should have one address")
+	}
+	for i := range statement.Pub_Vec_Key {
+		if !utils.Is_Valid_Point(statement.Pub_Vec_Key[i]) || Ethereum_Address(statement.Pub_Vec_Key[i]) != statement.Addresses[i] {
+			return errors.New("a public key does not match its address")
+		}
+	}
+	return nil
+}
+
+// Start the transcript of an Ethereum ring statement, binding the address list
+func (statement *EthereumStatement) newTranscript() *utils.Fiat_Shamir {
+	fs := statement.Statement.newTranscript("ethereum")
+	fs.Append_Int("addresses", len(statement.Addresses))
+	for i := range statement.Addresses {
+		fs.Append_Bytes("address", statement.Addresses[i][:])
+	}
+	return fs
+}
@@ -0,0 +1,88 @@
+package any_proofs
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+func uncompressedKey(secret *big.Int) []byte {
+	return secp256k1.PrivKeyFromBytes(secret.Bytes()).PubKey().SerializeUncompressed()
+}
+
+func TestEthereumAddress(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	P, err := Parse_Uncompressed_Key(uncompressedKey(big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := Ethereum_Address(P)
+	if hex.EncodeToString(address[:]) != strings.ToLower("7E5F4552091A69125d5DfCb7b8C2659029395Bdf") {
+		t.Fatalf("address of key 1 is %x", address)
+	}
+}
+
+func TestParseUncompressedKey(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	valid := uncompressedKey(big.NewInt(1))
+	badPrefix := append([]byte{0x02}, valid[1:]...)
+	offCurve := append([]byte{}, valid...)
+	offCurve[64] ^= 1
+	tests := []struct {
+		name string
+		key  []byte
+		ok   bool
+	}{
+		{"valid", valid, true},
+		{"missing prefix", valid[1:], false},
+		{"compressed prefix", badPrefix, false},
+		{"off the curve", offCurve, false},
+		{"too long", append(append([]byte{}, valid...), 0), false},
+	}
+	for _, test := range tests {
+		_, err := Parse_Uncompressed_Key(test.key)
+		if (err == nil) != test.ok {
+			t.Errorf("%s: err = %v", test.name, err)
+		}
+	}
+}
+
+func TestEthereumRing(t *testing.T) {
+	params, _, _, _, _ := testRing(8, nil)
+	var keys [][]byte
+	var secrets []*big.Int
+	for i := 0; i < 8; i++ {
+		secret := randomScalar()
+		keys = append(keys, uncompressedKey(secret))
+		if i%3 == 0 {
+			secrets = append(secrets, secret)
+		}
+	}
+	statement, err := params.EthereumRing(keys, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := EthereumProve(statement, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := EthereumVerify(statement, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+
+	// A mismatched address is rejected both when building the ring and when verifying
+	addresses := append([][20]byte{}, statement.Addresses...)
+	addresses[2][0] ^= 1
+	if _, err := params.EthereumRing(keys, addresses); err == nil {
+		t.Fatal("ring built with a wrong address")
+	}
+	statement.Addresses = addresses
+	if err := EthereumVerify(statement, proof); err == nil {
+		t.Fatal("proof accepted with a wrong address")
+	}
+}
@@ -0,0 +1,50 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Public statement of an exact-count proof: the ring and the disclosed number k of known keys
+type ExactCountStatement struct {
+	Statement
+	Count int // public number k of secret keys known by the prover
+}
+
+// Generate a proof that the prover knows exactly k = len(secrets) secret keys of the ring, disclosing k but not
+// which keys, by the extra constraint <b_0, 1^N> = k in the inner-product relation
+func (params *Params) ExactCountProve(ring []utils.Point, secrets []*big.Int) (ExactCountStatement, Proof, error) {
+	if err := params.checkRing(ring); err != nil {
+		return ExactCountStatement{}, Proof{}, err
+	}
+	b_0, keys, err := Generate_Selected_b_0(params.Public_ck, ring, secrets)
+	if err != nil {
+		return ExactCountStatement{}, Proof{}, err
+	}
+	statement := ExactCountStatement{Statement: params.Statement(ring), Count: len(secrets)}
+	proof := proveConstrained(statement.newTranscript(), statement.Statement, b_0, keys, Generate_Scalar_Vector(big.NewInt(1), len(ring)), big.NewInt(0))
+	return statement, proof, nil
+}
+
+// Verify that the prover knows exactly the disclosed number of secret keys of the ring
+func ExactCountVerify(statement ExactCountStatement, proof Proof) error {
+	if statement.Count < 0 || statement.Count > len(statement.Pub_Vec_Key) {
+		return errors.New("the count should lie between 0 and the ring size")
+	}
+	N := len(statement.Pub_Vec_Key)
+	if err := verifyConstrained(statement.newTranscript(), statement.Statement, Generate_Scalar_Vector(big.NewInt(1), N), big.NewInt(int64(statement.Count)), nil, proof); err != nil {
+		return errors.New("invalid exact-count proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// Start the transcript of an exact-count statement, binding the count k
+func (statement *ExactCountStatement) newTranscript() *utils.Fiat_Shamir {
+	fs := statement.Statement.newTranscript("exact_count")
+	fs.Append_Int("k", statement.Count)
+	return fs
+}
@@ -0,0 +1,47 @@
+package any_proofs
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestExactCount(t *testing.T) {
+	tests := []struct {
+		signers []int
+		claim   int // count in the verified statement
+		accept  bool
+	}{
+		{[]int{2, 5}, 2, true},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, 8, true},
+		{nil, 0, true},
+		{[]int{2, 5}, 1, false},
+		{[]int{2, 5}, 3, false},
+		{[]int{3}, -1, false},
+		{[]int{3}, 9, false},
+	}
+	for _, test := range tests {
+		params, ring, secrets, _, _ := testRing(8, test.signers)
+		statement, proof, err := params.ExactCountProve(ring, secrets)
+		if err != nil {
+			t.Fatalf("%d keys: %v", len(test.signers), err)
+		}
+		statement.Count = test.claim
+		if err := ExactCountVerify(statement, proof); (err == nil) != test.accept {
+			t.Errorf("%d keys claimed as %d: err = %v", len(test.signers), test.claim, err)
+		}
+	}
+}
+
+// A prover proving a smaller count than it uses in the constraint is rejected
+func TestExactCountWrongTotal(t *testing.T) {
+	params, ring, secrets, _, _ := testRing(8, []int{1, 2, 6})
+	b_0, keys, err := Generate_Selected_b_0(params.Public_ck, ring, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := ExactCountStatement{Statement: params.Statement(ring), Count: 2}
+	proof := proveConstrained(statement.newTranscript(), statement.Statement, b_0, keys, Generate_Scalar_Vector(big.NewInt(1), 8), big.NewInt(0))
+	if ExactCountVerify(statement, proof) == nil {
+		t.Fatal("proof of 3 keys accepted as 2")
+	}
+}
@@ -0,0 +1,227 @@
+package any_proofs
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+
+	"anyOutOfMany/utils"
+)
+
+// Public statement of a linkable proof: the ring and one key image I_j = sk_j H_p(pk_j) per known key,
+// or I_j = sk_j H(context, pk_j) when the tags are scoped to a context
+type LinkableStatement struct {
+	Statement
+	Context string        // linking context, e.g. an election or epoch, empty for global key images
+	Tags    []utils.Point // key images, sorted so that their order does not reveal the ring positions
+}
+
+// Ring signature showing that a key image belongs to some ring key known by the prover
+type TagProof struct {
+	C_0 *big.Int   // first challenge of the ring
+	S   []*big.Int // responses s_i of every ring position
+}
+
+// Linkable proof: a ring proof that the prover knows exactly len(Tags) keys, and a ring signature per tag
+type LinkableProof struct {
+	Proof
+	Tag_Proofs []TagProof
+}
+
+// Generate a linkable proof for the given secret keys of the ring, two proofs using the same secret key
+// publish the same key image
+func (params *Params) LinkableProve(ring []utils.Point, secrets []*big.Int) (LinkableStatement, LinkableProof, error) {
+	return params.ScopedProve(ring, secrets, "")
+}
+
+// Generate a linkable proof whose tags are scoped to the context, two proofs using the same secret key are
+// linked only if they share the context
+func (params *Params) ScopedProve(ring []utils.Point, secrets []*big.Int, context string) (LinkableStatement, LinkableProof, error) {
+	if err := params.checkRing(ring); err != nil {
+		return LinkableStatement{}, LinkableProof{}, err
+	}
+	b_0, keys, err := Generate_Selected_b_0(params.Public_ck, ring, secrets)
+	if err != nil {
+		return LinkableStatement{}, LinkableProof{}, err
+	}
+
+	// Compute the key images of the selected positions
+	statement := LinkableStatement{Statement: params.Statement(ring), Context: context}
+	bases := statement.tagBases()
+	var index []int
+	var tags []utils.Point
+	for i := range b_0 {
+		if b_0[i].Sign() != 0 {
+			index = append(index, i)
+			tags = append(tags, utils.Commit(bases[i], keys[i]))
+		}
+	}
+	order := make([]int, len(tags))
+	for j := range order {
+		order[j] = j
+	}
+	sort.Slice(order, func(a, b int) bool { return comparePoints(tags[order[a]], tags[order[b]]) < 0 })
+	for _, j := range order {
+		statement.Tags = append(statement.Tags, tags[j])
+	}
+
+	proof := LinkableProof{}
+	proof.Proof = proveConstrained(statement.newTranscript(), statement.Statement, b_0, keys, Generate_Scalar_Vector(big.NewInt(1), len(ring)), big.NewInt(0))
+	fs := statement.newTranscript()
+	for j, o := range order {
+		proof.Tag_Proofs = append(proof.Tag_Proofs, statement.signTag(*fs, bases, j, index[o], keys[index[o]]))
+	}
+	return statement, proof, nil
+}
+
+// Verify a linkable proof and return its key images, which callers compare with previously seen ones to detect reuse
+func LinkableVerify(statement LinkableStatement, proof LinkableProof) ([]utils.Point, error) {
+	N := len(statement.Pub_Vec_Key)
+	if len(statement.Tags) > N || len(proof.Tag_Proofs) != len(statement.Tags) {
+		return nil, errors.New("every key image should have one ring signature")
+	}
+	for j := range statement.Tags {
+		if !utils.Is_Valid_Point(statement.Tags[j]) {
+			return nil, errors.New("the proof contains an invalid point")
+		}
+		if j > 0 && comparePoints(statement.Tags[j-1], statement.Tags[j]) >= 0 {
+			return nil, errors.New("the key images should be distinct and sorted")
+		}
+	}
+	if err := verifyConstrained(statement.newTranscript(), statement.Statement, Generate_Scalar_Vector(big.NewInt(1), N), big.NewInt(int64(len(statement.Tags))), nil, proof.Proof); err != nil {
+		return nil, errors.New("invalid linkable proof")
+	}
+	fs := statement.newTranscript()
+	bases := statement.tagBases()
+	for j := range statement.Tags {
+		if err := statement.verifyTag(*fs, bases, j, proof.Tag_Proofs[j]); err != nil {
+			return nil, err
+		}
+	}
+	return statement.Tags, nil
+}
+
+// Check whether two tag sets share a tag, i.e., some secret key was used in both proofs of the same context
+func Linked(tagsA []utils.Point, tagsB []utils.Point) bool {
+	for _, a := range tagsA {
+		for _, b := range tagsB {
+			if utils.Is_Equal_Point(a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Base point H_p(pk) of the key image of a public key
+func Key_Image_Base(pk utils.Point) utils.Point {
+	return utils.Hash_Point_To_Point("any_proofs/key_image", pk)
+}
+
+// Base point H(context, pk) of the tag of a public key scoped to a context
+func Scoped_Image_Base(context string, pk utils.Point) utils.Point {
+	var fs utils.Fiat_Shamir
+	fs.New("any_proofs/scoped_image")
+	fs.Append_Bytes("context", []byte(context))
+	fs.Append_Point("pk", pk)
+	return utils.Hash_To_Point("any_proofs/scoped_image", fs.Challenge("base").Bytes())
+}
+
+////////////////////////Private functions
+
+// Start the transcript of a linkable statement, binding the context and the key images
+func (statement *LinkableStatement) newTranscript() *utils.Fiat_Shamir {
+	fs := statement.Statement.newTranscript("linkable")
+	fs.Append_Bytes("context", []byte(statement.Context))
+	fs.Append_Points("I", statement.Tags)
+	return fs
+}
+
+// Base point of the tag of a ring key
+func (statement *LinkableStatement) tagBase(pk utils.Point) utils.Point {
+	if statement.Context == "" {
+		return Key_Image_Base(pk)
+	}
+	return Scoped_Image_Base(statement.Context, pk)
+}
+
+// Tag bases of every ring key
+func (statement *LinkableStatement) tagBases() []utils.Point {
+	var bases []utils.Point
+	for _, pk := range statement.Pub_Vec_Key {
+		bases = append(bases, statement.tagBase(pk))
+	}
+	return bases
+}
+
+// Sign the j-th key image with the secret key at ring position pi: a ring signature over the pairs (ck, H_p(P_i))
+// showing log_ck P_i = log_{H_p(P_i)} I_j for some i, with H(context, P_i) in place of H_p(P_i) for scoped tags.
+// fs is the statement transcript and bases the tag bases of the ring
+func (statement *LinkableStatement) signTag(fs utils.Fiat_Shamir, bases []utils.Point, j int, pi int, sk *big.Int) TagProof {
+	ring := statement.Pub_Vec_Key
+	N := len(ring)
+	tag := statement.Tags[j]
+	c := make([]*big.Int, N)
+	s := make([]*big.Int, N)
+	fs.Append_Int("tag", j)
+	nonces := hedgedNonces(&fs, []*big.Int{sk})
+
+	alpha := nonces.Scalar()
+	L := utils.Commit(statement.Public_ck, alpha)
+	R := utils.Commit(bases[pi], alpha)
+	c[(pi+1)%N] = tagChallenge(fs, L, R)
+	for i := (pi + 1) % N; i != pi; i = (i + 1) % N {
+		s[i] = nonces.Scalar()
+		L, R = tagCommitments(statement.Public_ck, ring[i], bases[i], tag, s[i], c[i])
+		c[(i+1)%N] = tagChallenge(fs, L, R)
+	}
+	s[pi] = utils.Sub_In_P(alpha, utils.Mul_In_P(c[pi], sk))
+	return TagProof{C_0: c[0], S: s}
+}
+
+// Verify the ring signature of the j-th key image by recomputing the ring of challenges
+func (statement *LinkableStatement) verifyTag(fs utils.Fiat_Shamir, bases []utils.Point, j int, proof TagProof) error {
+	N := len(statement.Pub_Vec_Key)
+	if len(proof.S) != N || !utils.Is_Valid_Scalar(proof.C_0) {
+		return errors.New("the key image signature is malformed")
+	}
+	fs.Append_Int("tag", j)
+	c := proof.C_0
+	for i := 0; i < N; i++ {
+		if !utils.Is_Valid_Scalar(proof.S[i]) {
+			return errors.New("the key image signature is malformed")
+		}
+		L, R := tagCommitments(statement.Public_ck, statement.Pub_Vec_Key[i], bases[i], statement.Tags[j], proof.S[i], c)
+		c = tagChallenge(fs, L, R)
+	}
+	if c.Cmp(proof.C_0) != 0 {
+		return errors.New("invalid key image signature")
+	}
+	return nil
+}
+
+// Commitments L = ck^s P^c, R = base^s I^c of one ring position
+func tagCommitments(ck utils.Point, P utils.Point, base utils.Point, tag utils.Point, s *big.Int, c *big.Int) (utils.Point, utils.Point) {
+	L := utils.Multi_Scalar_Mult([]utils.Point{ck, P}, []*big.Int{s, c})
+	R := utils.Multi_Scalar_Mult([]utils.Point{base, tag}, []*big.Int{s, c})
+	return L, R
+}
+
+// Challenge following the commitments L, R in the ring signature of a key image, fs is a copy of the
+// transcript of the statement and the key image index so that it is hashed only once per signature
+func tagChallenge(fs utils.Fiat_Shamir, L utils.Point, R utils.Point) *big.Int {
+	fs.Append_Point("L", L)
+	fs.Append_Point("R", R)
+	return fs.Challenge("c")
+}
+
+// Order points by their 64-byte encoding X||Y
+func comparePoints(a utils.Point, b utils.Point) int {
+	var ka, kb [64]byte
+	a.X.FillBytes(ka[:32])
+	a.Y.FillBytes(ka[32:])
+	b.X.FillBytes(kb[:32])
+	b.Y.FillBytes(kb[32:])
+	return bytes.Compare(ka[:], kb[:])
+}
@@ -0,0 +1,146 @@
+package any_proofs
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+)
+
+// Two proofs are linked exactly when they use a common secret key
+func TestLinkable(t *testing.T) {
+	params, ring, secrets, _, _ := testRing(8, []int{1, 4, 6})
+	cases := []struct {
+		name   string
+		first  []int
+		second []int
+		linked bool
+	}{
+		{"disjoint keys", []int{0}, []int{1, 2}, false},
+		{"common key", []int{0, 2}, []int{2}, true},
+		{"same keys", []int{0, 1}, []int{1, 0}, true},
+	}
+	for _, c := range cases {
+		var tags [2][]utils.Point
+		for p, use := range [][]int{c.first, c.second} {
+			var keys []*big.Int
+			for _, i := range use {
+				keys = append(keys, secrets[i])
+			}
+			statement, proof, err := params.LinkableProve(ring, keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tags[p], err = LinkableVerify(statement, proof)
+			if err != nil {
+				t.Fatalf("%s: honest proof rejected: %v", c.name, err)
+			}
+			if len(tags[p]) != len(keys) {
+				t.Fatalf("%s: %d key images for %d keys", c.name, len(tags[p]), len(keys))
+			}
+		}
+		if Linked(tags[0], tags[1]) != c.linked {
+			t.Errorf("%s: linked = %v, want %v", c.name, !c.linked, c.linked)
+		}
+	}
+}
+
+// A proof cannot publish the same key image twice, i.e., count one key as two
+func TestLinkableDuplicateTag(t *testing.T) {
+	params, ring, secrets, _, _ := testRing(8, []int{2, 5})
+	statement, proof, err := params.LinkableProve(ring, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	duplicated := statement
+	duplicated.Tags = []utils.Point{statement.Tags[0], statement.Tags[0]}
+	forged := proof
+	forged.Tag_Proofs = []TagProof{proof.Tag_Proofs[0], proof.Tag_Proofs[0]}
+	if _, err := LinkableVerify(duplicated, forged); err == nil {
+		t.Fatal("proof with a duplicated key image accepted")
+	}
+
+	// Signing the same key twice gives the same image, which is refused
+	_, _, err = params.LinkableProve(ring, []*big.Int{secrets[0], secrets[0]})
+	if err == nil {
+		t.Fatal("proof generated with a repeated secret key")
+	}
+}
+
+func TestLinkableTamperedTag(t *testing.T) {
+	params, ring, secrets, _, _ := testRing(8, []int{3, 7})
+	statement, proof, err := params.LinkableProve(ring, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := randomScalar()
+	tamperings := map[string]utils.Point{
+		"random point":     utils.GeneratePoint(),
+		"image of non-key": utils.Commit(Key_Image_Base(ring[3]), other),
+		"shifted image":    utils.Cal_Point_Add(statement.Tags[1], Key_Image_Base(ring[3])),
+	}
+	for name, tag := range tamperings {
+		tampered := statement
+		tampered.Tags = []utils.Point{statement.Tags[0], tag}
+		if comparePoints(tag, statement.Tags[0]) < 0 {
+			tampered.Tags = []utils.Point{tag, statement.Tags[0]}
+		}
+		if _, err := LinkableVerify(tampered, proof); err == nil {
+			t.Errorf("proof with a tampered key image (%s) accepted", name)
+		}
+	}
+
+	// Another response in a tag signature breaks its ring of challenges
+	tampered := proof
+	tampered.Tag_Proofs = append([]TagProof{}, proof.Tag_Proofs...)
+	tampered.Tag_Proofs[1].S = append([]*big.Int{}, proof.Tag_Proofs[1].S...)
+	tampered.Tag_Proofs[1].S[0] = utils.Add_In_P(tampered.Tag_Proofs[1].S[0], big.NewInt(1))
+	if _, err := LinkableVerify(statement, tampered); err == nil {
+		t.Error("tag signature with a tampered response accepted")
+	}
+	if _, err := LinkableVerify(statement, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+}
+
+// Scoped tags link proofs of the same context only, and a proof does not verify under another context
+func TestScopedTags(t *testing.T) {
+	params, ring, secrets, _, _ := testRing(8, []int{0, 3})
+	prove := func(context string) (LinkableStatement, LinkableProof, []utils.Point) {
+		statement, proof, err := params.ScopedProve(ring, secrets[:1], context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tags, err := LinkableVerify(statement, proof)
+		if err != nil {
+			t.Fatalf("honest proof for context %q rejected: %v", context, err)
+		}
+		return statement, proof, tags
+	}
+	statement, proof, vote := prove("election 1")
+	_, _, revote := prove("election 1")
+	_, _, other := prove("election 2")
+	_, _, global := prove("")
+	cases := []struct {
+		name   string
+		tags   []utils.Point
+		linked bool
+	}{
+		{"same context", revote, true},
+		{"other context", other, false},
+		{"global key image", global, false},
+	}
+	for _, c := range cases {
+		if Linked(vote, c.tags) != c.linked {
+			t.Errorf("%s: linked = %v, want %v", c.name, !c.linked, c.linked)
+		}
+	}
+
+	for _, context := range []string{"election 2", ""} {
+		replayed := statement
+		replayed.Context = context
+		if _, err := LinkableVerify(replayed, proof); err == nil {
+			t.Errorf("proof for context %q accepted for context %q", statement.Context, context)
+		}
+	}
+}
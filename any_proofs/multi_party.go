@@ -0,0 +1,473 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"anyOutOfMany/utils"
+)
+
+// Multi-party proving: every term of the relation splits over the ring positions, so each key holder computes
+// its shares of A, B, T1, T2, E, tau_x, mu, f_s over the positions of its own keys, and the coordinator covers
+// the remaining positions with b_0 = 0. The coordinator learns the selected positions but no secret key, and
+// the aggregated proof is an ordinary any-out-of-many proof. Every participant first commits to the hash of its
+// shares of A, B and reveals them only once all hashes are fixed, so that no participant chooses its shares
+// after seeing the others', which would open the ROS attack on concurrent sessions. The nonces of a participant
+// are hedged but never deterministic: the challenges depend on the other participants' shares, so the same nonces
+// answering two sets of challenges would reveal the secret keys
+
+// Hash committing a participant to its first round message
+type Party_Precommitment struct {
+	Hash *big.Int
+}
+
+// Precommitments of all participants including the coordinator, after which the first round is revealed
+type Party_Precommitments struct {
+	Hashes []*big.Int
+}
+
+// First round message of a participant
+type Party_Commitment struct {
+	A, B utils.Point
+}
+
+// Second round message of a participant
+type Party_Commitment_T struct {
+	T1, T2, E utils.Point
+}
+
+// Final message of a participant: its openings at its positions and its response shares
+type Party_Response struct {
+	Positions      []int
+	Zeta, Eta      []*big.Int
+	Tau_x, Mu, F_s *big.Int
+}
+
+// Revealed first round commitments of all participants, their sums A, B and the challenges y, z derived from them
+type Party_Challenge_YZ struct {
+	Commitments []Party_Commitment
+	A, B        utils.Point
+	Y, Z        *big.Int
+}
+
+// Aggregated second round commitments and the challenge x derived from them
+type Party_Challenge_X struct {
+	T1, T2, E utils.Point
+	X         *big.Int
+}
+
+// Rounds of a participant, a participant runs every round once
+const (
+	roundNew = iota
+	roundPre
+	roundAB
+	roundT
+	roundDone
+)
+
+// Participant owning a set of ring positions, it is single-use since its nonces answer only one set of challenges
+type Party struct {
+	statement Statement
+	positions []int
+	round     int
+	fs        *utils.Fiat_Shamir // transcript of the aggregated messages
+	b_0, keys []*big.Int         // selector bits and secret keys at the positions
+
+	// first round message and the precommitments of all participants
+	commitment Party_Commitment
+	hashes     []*big.Int
+
+	// nonces, drawn in the round that first uses them from a source hedged with fresh randomness
+	nonces                         *utils.Nonce_Source
+	s_0, s_1                       []*big.Int
+	alpha, beta, tau_1, tau_2, r_s *big.Int
+
+	// challenges and the vectors at the positions
+	y, z     *big.Int
+	yN       []*big.Int
+	l_0, r_0 []*big.Int
+	r_1      []*big.Int
+}
+
+// Coordinator collecting the shares of all participants
+type Coordinator struct {
+	statement Statement
+	taken     []bool
+	own       *Party
+	fs        *utils.Fiat_Shamir // transcript of the aggregated messages
+
+	pre Party_Precommitments
+	yz  Party_Challenge_YZ
+	x   Party_Challenge_X
+}
+
+// Create a key holder for its secret keys of the ring
+func (params *Params) NewParty(ring []utils.Point, secrets []*big.Int) (*Party, error) {
+	if err := params.checkRing(ring); err != nil {
+		return nil, err
+	}
+	if len(secrets) == 0 {
+		return nil, errors.New("a key holder should have at least one secret key")
+	}
+	b_0, keys, err := Generate_Selected_b_0(params.Public_ck, ring, secrets)
+	if err != nil {
+		return nil, err
+	}
+	var positions []int
+	for i := range b_0 {
+		if b_0[i].Sign() != 0 {
+			positions = append(positions, i)
+		}
+	}
+	return newParty(params.Statement(ring), positions, b_0, keys), nil
+}
+
+// Ring positions of the key holder, sent to the coordinator only
+func (party *Party) Positions() []int {
+	return party.positions
+}
+
+// First round, commit phase: the hash of A = g^{b_0} h^{b_1} u^alpha, B = g^{s_0} h^{s_1} u^beta restricted
+// to the positions
+func (party *Party) Precommit() (Party_Precommitment, error) {
+	if party.round != roundNew {
+		return Party_Precommitment{}, errors.New("the first round has already been run")
+	}
+	party.round = roundPre
+	party.nonces = hedgedNonces(party.fs, party.b_0, party.keys)
+	party.s_0 = party.nonces.Scalar_Vector(len(party.positions))
+	party.s_1 = party.nonces.Scalar_Vector(len(party.positions))
+	party.alpha = party.nonces.Scalar()
+	party.beta = party.nonces.Scalar()
+
+	var Vec_G, Vec_H []utils.Point
+	for _, i := range party.positions {
+		Vec_G = append(Vec_G, party.statement.Gen_Vec_G[i])
+		Vec_H = append(Vec_H, party.statement.Gen_Vec_H[i])
+	}
+	b_1 := Generate_b_1(party.b_0)
+	A := utils.Cal_Point_Add(utils.Pedersen_Commit_Vector(Vec_G, Vec_H, party.b_0, b_1), utils.Commit(party.statement.Gen_u, party.alpha))
+	B := utils.Cal_Point_Add(utils.Pedersen_Commit_Vector(Vec_G, Vec_H, party.s_0, party.s_1), utils.Commit(party.statement.Gen_u, party.beta))
+	party.commitment = Party_Commitment{A: A, B: B}
+	return Party_Precommitment{Hash: precommitHash(party.commitment)}, nil
+}
+
+// First round, reveal phase: A, B once the precommitments of all participants are fixed
+func (party *Party) CommitAB(precommitments Party_Precommitments) (Party_Commitment, error) {
+	if party.round != roundPre {
+		return Party_Commitment{}, errors.New("the first round should be precommitted and revealed once")
+	}
+	party.round = roundAB
+	if countHash(precommitments.Hashes, precommitHash(party.commitment)) != 1 {
+		party.round = roundDone
+		return Party_Commitment{}, errors.New("the precommitments should contain the participant's hash once")
+	}
+	party.hashes = append([]*big.Int{}, precommitments.Hashes...)
+	return party.commitment, nil
+}
+
+// Second round: the shares of t_1, t_2 and E at the positions, after checking that every revealed commitment
+// opens one precommitment, that A, B are their sums and that the challenges y, z are derived from A, B
+func (party *Party) CommitT(challenge Party_Challenge_YZ) (Party_Commitment_T, error) {
+	if party.round != roundAB {
+		return Party_Commitment_T{}, errors.New("the second round should follow the first one and run once")
+	}
+	A, B, err := openPrecommitments(party.hashes, challenge.Commitments)
+	if err != nil {
+		party.round = roundDone
+		return Party_Commitment_T{}, err
+	}
+	if !utils.Is_Valid_Point(challenge.A) || !utils.Is_Valid_Point(challenge.B) || !utils.Is_Equal_Point(A, challenge.A) || !utils.Is_Equal_Point(B, challenge.B) {
+		party.round = roundDone
+		return Party_Commitment_T{}, errors.New("the aggregated commitments are not the sums of the revealed ones")
+	}
+	party.fs.Append_Point("A", challenge.A)
+	party.fs.Append_Point("B", challenge.B)
+	y := party.fs.Challenge("y")
+	z := party.fs.Challenge("z")
+	if challenge.Y == nil || challenge.Z == nil || y.Cmp(challenge.Y) != 0 || z.Cmp(challenge.Z) != 0 {
+		party.round = roundDone
+		return Party_Commitment_T{}, errors.New("the challenges y, z do not match the aggregated commitments")
+	}
+	party.round = roundT
+	party.y = y
+	party.z = z
+	party.tau_1 = party.nonces.Scalar()
+	party.tau_2 = party.nonces.Scalar()
+	party.r_s = party.nonces.Scalar()
+
+	var P_yN_s0 []utils.Point
+	var yN_s0 []*big.Int
+	for j, i := range party.positions {
+		yi := new(big.Int).Exp(y, big.NewInt(int64(i)), utils.Curve.N)
+		b_1 := utils.Sub_In_P(big.NewInt(1), party.b_0[j])
+		party.yN = append(party.yN, yi)
+		party.l_0 = append(party.l_0, utils.Add_In_P(party.b_0[j], z))
+		party.r_0 = append(party.r_0, utils.Mul_In_P(yi, utils.Add_In_P(b_1, z)))
+		party.r_1 = append(party.r_1, utils.Mul_In_P(yi, party.s_1[j]))
+		P_yN_s0 = append(P_yN_s0, party.statement.Pub_Vec_Key[i])
+		yN_s0 = append(yN_s0, utils.Mul_In_P(yi, party.s_0[j]))
+	}
+	t_1 := utils.Add_In_P(utils.Cal_IP_Vec(party.l_0, party.r_1), utils.Cal_IP_Vec(party.s_0, party.r_0))
+	t_2 := utils.Cal_IP_Vec(party.s_0, party.r_1)
+	T1 := utils.Pedersen_Commit(party.statement.Gen_v, party.statement.Gen_u, t_1, party.tau_1)
+	T2 := utils.Pedersen_Commit(party.statement.Gen_v, party.statement.Gen_u, t_2, party.tau_2)
+	E := utils.Cal_Point_Add(utils.Commit_Vector(P_yN_s0, yN_s0), utils.Commit(party.statement.Public_ck, utils.Neg_Zp(party.r_s)))
+	return Party_Commitment_T{T1: T1, T2: T2, E: E}, nil
+}
+
+// Final round: the openings zeta, eta at the positions and the shares of tau_x, mu, f_s, after checking that
+// the challenge x is derived from the aggregated commitments
+func (party *Party) Respond(challenge Party_Challenge_X) (Party_Response, error) {
+	if party.round != roundT {
+		return Party_Response{}, errors.New("the final round should follow the second one and run once")
+	}
+	party.round = roundDone
+	if !utils.Is_Valid_Point(challenge.T1) || !utils.Is_Valid_Point(challenge.T2) || !utils.Is_Valid_Point(challenge.E) {
+		return Party_Response{}, errors.New("the aggregated commitments should be points on the curve")
+	}
+	party.fs.Append_Point("T1", challenge.T1)
+	party.fs.Append_Point("T2", challenge.T2)
+	party.fs.Append_Point("E", challenge.E)
+	x := party.fs.Challenge("x")
+	if challenge.X == nil || x.Cmp(challenge.X) != 0 {
+		return Party_Response{}, errors.New("the challenge x does not match the aggregated commitments")
+	}
+
+	response := Party_Response{Positions: party.positions}
+	for j := range party.positions {
+		response.Zeta = append(response.Zeta, utils.Add_In_P(party.l_0[j], utils.Mul_In_P(party.s_0[j], x)))
+		response.Eta = append(response.Eta, utils.Add_In_P(party.r_0[j], utils.Mul_In_P(party.r_1[j], x)))
+	}
+	response.Tau_x = utils.Add_In_P(utils.Mul_In_P(party.tau_1, x), utils.Mul_In_P(party.tau_2, utils.Mul_In_P(x, x)))
+	response.Mu = utils.Add_In_P(party.alpha, utils.Mul_In_P(party.beta, x))
+	response.F_s = utils.Add_In_P(utils.Mul_In_P(party.r_s, x), utils.Cal_IP_Vec(utils.Cal_HP_Vec(party.yN, party.b_0), party.keys))
+	return response, nil
+}
+
+// Create the coordinator of a multi-party proof over the ring
+func (params *Params) NewCoordinator(ring []utils.Point) (*Coordinator, error) {
+	if err := params.checkRing(ring); err != nil {
+		return nil, err
+	}
+	return &Coordinator{statement: params.Statement(ring), taken: make([]bool, len(ring))}, nil
+}
+
+// Register the positions of a key holder, the positions of different key holders should be disjoint
+func (coordinator *Coordinator) Register(positions []int) error {
+	if coordinator.own != nil {
+		return errors.New("the proof has already started")
+	}
+	for _, i := range positions {
+		if i < 0 || i >= len(coordinator.taken) || coordinator.taken[i] {
+			return errors.New("the positions should be distinct ring positions")
+		}
+	}
+	for _, i := range positions {
+		coordinator.taken[i] = true
+	}
+	return nil
+}
+
+// Collect the precommitments of the participants together with the coordinator's own
+func (coordinator *Coordinator) Precommitments(precommitments []Party_Precommitment) (Party_Precommitments, error) {
+	if coordinator.own != nil {
+		return Party_Precommitments{}, errors.New("the proof has already started")
+	}
+	var positions []int
+	for i, taken := range coordinator.taken {
+		if !taken {
+			positions = append(positions, i)
+		}
+	}
+	N := len(coordinator.taken)
+	zero := Generate_Scalar_Vector(big.NewInt(0), N)
+	coordinator.own = newParty(coordinator.statement, positions, zero, zero)
+
+	own, err := coordinator.own.Precommit()
+	if err != nil {
+		return Party_Precommitments{}, err
+	}
+	for _, precommitment := range append(precommitments, own) {
+		if !utils.Is_Valid_Scalar(precommitment.Hash) {
+			return Party_Precommitments{}, errors.New("the precommitments should be hashes in Zp")
+		}
+		coordinator.pre.Hashes = append(coordinator.pre.Hashes, precommitment.Hash)
+	}
+	return coordinator.pre, nil
+}
+
+// Check the revealed first round messages against the precommitments and derive the challenges y, z
+func (coordinator *Coordinator) ChallengeYZ(commitments []Party_Commitment) (Party_Challenge_YZ, error) {
+	if coordinator.own == nil || coordinator.fs != nil {
+		return Party_Challenge_YZ{}, errors.New("the first round should be precommitted and revealed once")
+	}
+	own, err := coordinator.own.CommitAB(coordinator.pre)
+	if err != nil {
+		return Party_Challenge_YZ{}, err
+	}
+	commitments = append(append([]Party_Commitment{}, commitments...), own)
+	A, B, err := openPrecommitments(coordinator.pre.Hashes, commitments)
+	if err != nil {
+		return Party_Challenge_YZ{}, err
+	}
+	coordinator.fs = coordinator.statement.newTranscript("plain")
+	coordinator.fs.Append_Point("A", A)
+	coordinator.fs.Append_Point("B", B)
+	coordinator.yz = Party_Challenge_YZ{Commitments: commitments, A: A, B: B, Y: coordinator.fs.Challenge("y"), Z: coordinator.fs.Challenge("z")}
+	return coordinator.yz, nil
+}
+
+// Aggregate the second round messages and derive the challenge x
+func (coordinator *Coordinator) ChallengeX(commitments []Party_Commitment_T) (Party_Challenge_X, error) {
+	if coordinator.fs == nil {
+		return Party_Challenge_X{}, errors.New("the first round has not been aggregated")
+	}
+	own, err := coordinator.own.CommitT(coordinator.yz)
+	if err != nil {
+		return Party_Challenge_X{}, err
+	}
+	T1, T2, E := own.T1, own.T2, own.E
+	for _, commitment := range commitments {
+		if !utils.Is_Valid_Point(commitment.T1) || !utils.Is_Valid_Point(commitment.T2) || !utils.Is_Valid_Point(commitment.E) {
+			return Party_Challenge_X{}, errors.New("the commitments should be points on the curve")
+		}
+		T1 = utils.Cal_Point_Add(T1, commitment.T1)
+		T2 = utils.Cal_Point_Add(T2, commitment.T2)
+		E = utils.Cal_Point_Add(E, commitment.E)
+	}
+	coordinator.fs.Append_Point("T1", T1)
+	coordinator.fs.Append_Point("T2", T2)
+	coordinator.fs.Append_Point("E", E)
+	coordinator.x = Party_Challenge_X{T1: T1, T2: T2, E: E, X: coordinator.fs.Challenge("x")}
+	return coordinator.x, nil
+}
+
+// Aggregate the responses into one proof, compressing the openings by the inner-product argument
+func (coordinator *Coordinator) Finish(responses []Party_Response) (Statement, Proof, error) {
+	if coordinator.fs == nil {
+		return Statement{}, Proof{}, errors.New("the first round has not been aggregated")
+	}
+	own, err := coordinator.own.Respond(coordinator.x)
+	if err != nil {
+		return Statement{}, Proof{}, err
+	}
+	statement := coordinator.statement
+	N := len(statement.Pub_Vec_Key)
+	responses = append(responses, own)
+
+	zeta := make([]*big.Int, N)
+	eta := make([]*big.Int, N)
+	tau_x, mu, f_s := big.NewInt(0), big.NewInt(0), big.NewInt(0)
+	for _, response := range responses {
+		if len(response.Zeta) != len(response.Positions) || len(response.Eta) != len(response.Positions) {
+			return Statement{}, Proof{}, errors.New("a response should open every position of its key holder")
+		}
+		for j, i := range response.Positions {
+			if i < 0 || i >= N || zeta[i] != nil {
+				return Statement{}, Proof{}, errors.New("the responses should cover distinct ring positions")
+			}
+			zeta[i] = response.Zeta[j]
+			eta[i] = response.Eta[j]
+		}
+		tau_x = utils.Add_In_P(tau_x, response.Tau_x)
+		mu = utils.Add_In_P(mu, response.Mu)
+		f_s = utils.Add_In_P(f_s, response.F_s)
+	}
+	for i := range zeta {
+		if zeta[i] == nil {
+			return Statement{}, Proof{}, errors.New("a registered key holder has not responded")
+		}
+	}
+
+	// Compress the openings over generators g \circ P^{y^N} and h^{y^{-N}}
+	YN := Generate_Exp_Scalar_Vector(coordinator.yz.Y, N)
+	Inv_YN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(coordinator.yz.Y), N)
+	Inv_Vec_H := utils.Generate_Point_Vector_with_y(statement.Gen_Vec_H[:N], Inv_YN)
+	Vec_G_P := utils.Cal_Point_Add_Vec(statement.Gen_Vec_G[:N], utils.Generate_Point_Vector_with_y(statement.Pub_Vec_Key, YN))
+	L, R, C_zeta, C_eta := utils.Opt_Prove(coordinator.fs, zeta, eta, Vec_G_P, Inv_Vec_H, statement.Gen_v)
+
+	proof := Proof{
+		A:      coordinator.yz.A,
+		B:      coordinator.yz.B,
+		T1:     coordinator.x.T1,
+		T2:     coordinator.x.T2,
+		E:      coordinator.x.E,
+		Tau_x:  tau_x,
+		Mu:     mu,
+		Ip:     utils.Cal_IP_Vec(zeta, eta),
+		F_s:    f_s,
+		L:      L,
+		R:      R,
+		C_zeta: C_zeta[0],
+		C_eta:  C_eta[0],
+	}
+	return statement, proof, nil
+}
+
+////////////////////////Private functions
+
+// Create a participant for the given positions, b_0 and keys are indexed by ring position
+func newParty(statement Statement, positions []int, b_0 []*big.Int, keys []*big.Int) *Party {
+	sort.Ints(positions)
+	party := &Party{statement: statement, positions: positions, fs: statement.newTranscript("plain")}
+	for _, i := range positions {
+		party.b_0 = append(party.b_0, b_0[i])
+		party.keys = append(party.keys, keys[i])
+	}
+	return party
+}
+
+// Hash of a first round message under its own domain
+func precommitHash(commitment Party_Commitment) *big.Int {
+	var fs utils.Fiat_Shamir
+	fs.New("any_proofs/multi_party")
+	fs.Append_Point("A", commitment.A)
+	fs.Append_Point("B", commitment.B)
+	return fs.Challenge("precommitment")
+}
+
+// Number of occurrences of a hash in a list
+func countHash(hashes []*big.Int, hash *big.Int) int {
+	count := 0
+	for _, h := range hashes {
+		if h != nil && h.Cmp(hash) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Check that the revealed commitments open the precommitments one to one and return their sums A, B
+func openPrecommitments(hashes []*big.Int, commitments []Party_Commitment) (utils.Point, utils.Point, error) {
+	if len(commitments) == 0 || len(commitments) != len(hashes) {
+		return utils.Point{}, utils.Point{}, errors.New("every precommitment should be revealed once")
+	}
+	opened := make([]bool, len(hashes))
+	var A, B utils.Point
+	for j, commitment := range commitments {
+		if !utils.Is_Valid_Point(commitment.A) || !utils.Is_Valid_Point(commitment.B) {
+			return utils.Point{}, utils.Point{}, errors.New("the commitments should be points on the curve")
+		}
+		hash := precommitHash(commitment)
+		found := false
+		for i := range hashes {
+			if !opened[i] && hashes[i] != nil && hashes[i].Cmp(hash) == 0 {
+				opened[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return utils.Point{}, utils.Point{}, errors.New("a revealed commitment does not open a precommitment")
+		}
+		if j == 0 {
+			A, B = commitment.A, commitment.B
+		} else {
+			A = utils.Cal_Point_Add(A, commitment.A)
+			B = utils.Cal_Point_Add(B, commitment.B)
+		}
+	}
+	return A, B, nil
+}
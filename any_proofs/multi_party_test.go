@@ -0,0 +1,186 @@
+package any_proofs
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+)
+
+// Two key holders of a ring of 8 keys with a registered coordinator
+func multiPartySetup(t *testing.T) (*Coordinator, []*Party) {
+	params, ring, secrets, _, _ := testRing(8, []int{1, 4, 6})
+	var parties []*Party
+	coordinator, err := params.NewCoordinator(ring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, keys := range [][]*big.Int{secrets[:1], secrets[1:]} {
+		party, err := params.NewParty(ring, keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := coordinator.Register(party.Positions()); err != nil {
+			t.Fatal(err)
+		}
+		parties = append(parties, party)
+	}
+	return coordinator, parties
+}
+
+// Precommit and reveal the first round of every participant
+func firstRound(t *testing.T, coordinator *Coordinator, parties []*Party) Party_Challenge_YZ {
+	var precommitments []Party_Precommitment
+	for _, party := range parties {
+		precommitment, err := party.Precommit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		precommitments = append(precommitments, precommitment)
+	}
+	hashes, err := coordinator.Precommitments(precommitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var commitments []Party_Commitment
+	for _, party := range parties {
+		commitment, err := party.CommitAB(hashes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitments = append(commitments, commitment)
+	}
+	yz, err := coordinator.ChallengeYZ(commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return yz
+}
+
+func TestMultiParty(t *testing.T) {
+	coordinator, parties := multiPartySetup(t)
+	yz := firstRound(t, coordinator, parties)
+	var commitments_T []Party_Commitment_T
+	for _, party := range parties {
+		commitment, err := party.CommitT(yz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitments_T = append(commitments_T, commitment)
+	}
+	x, err := coordinator.ChallengeX(commitments_T)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var responses []Party_Response
+	for _, party := range parties {
+		response, err := party.Respond(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		responses = append(responses, response)
+	}
+	statement, proof, err := coordinator.Finish(responses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(statement, proof); err != nil {
+		t.Fatalf("aggregated proof rejected: %v", err)
+	}
+
+	// Every round runs once
+	if _, err := parties[0].Precommit(); err == nil {
+		t.Fatal("first round rerun")
+	}
+	if _, err := parties[0].CommitAB(Party_Precommitments{}); err == nil {
+		t.Fatal("first round revealed again")
+	}
+	if _, err := parties[0].CommitT(yz); err == nil {
+		t.Fatal("second round rerun")
+	}
+	x.X = utils.Add_In_P(x.X, big.NewInt(1))
+	if _, err := parties[0].Respond(x); err == nil {
+		t.Fatal("final round rerun for another challenge")
+	}
+}
+
+func TestMultiPartyChallenges(t *testing.T) {
+	coordinator, parties := multiPartySetup(t)
+	yz := firstRound(t, coordinator, parties)
+
+	// A challenge not derived from the aggregated commitments is refused
+	forged := yz
+	forged.Z = utils.Add_In_P(yz.Z, big.NewInt(1))
+	if _, err := parties[0].CommitT(forged); err == nil {
+		t.Fatal("forged challenge z accepted")
+	}
+	if _, err := parties[0].CommitT(yz); err == nil {
+		t.Fatal("party continued after a forged challenge")
+	}
+	commitment, err := parties[1].CommitT(yz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := coordinator.ChallengeX([]Party_Commitment_T{commitment})
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.T1 = utils.Cal_Point_Add(x.T1, coordinator.statement.Gen_v)
+	if _, err := parties[1].Respond(x); err == nil {
+		t.Fatal("challenge x of other commitments accepted")
+	}
+}
+
+// The first round messages are bound to the precommitments before any of them is revealed
+func TestMultiPartyPrecommitments(t *testing.T) {
+	coordinator, parties := multiPartySetup(t)
+	var precommitments []Party_Precommitment
+	for _, party := range parties {
+		precommitment, err := party.Precommit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		precommitments = append(precommitments, precommitment)
+	}
+
+	// A participant whose hash is missing does not reveal
+	if _, err := parties[0].CommitAB(Party_Precommitments{Hashes: []*big.Int{precommitments[1].Hash}}); err == nil {
+		t.Fatal("commitment revealed without its precommitment")
+	}
+
+	hashes, err := coordinator.Precommitments(precommitments[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment, err := parties[1].CommitAB(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A commitment chosen after the precommitments is refused
+	forged := commitment
+	forged.A = utils.Cal_Point_Add(commitment.A, coordinator.statement.Gen_u)
+	if _, err := coordinator.ChallengeYZ([]Party_Commitment{forged}); err == nil {
+		t.Fatal("commitment not matching its precommitment accepted")
+	}
+}
+
+// A participant checks the revealed commitments and their sums before answering the challenges y, z
+func TestMultiPartyReveals(t *testing.T) {
+	for _, name := range []string{"sum", "reveal", "missing"} {
+		coordinator, parties := multiPartySetup(t)
+		yz := firstRound(t, coordinator, parties)
+		switch name {
+		case "sum":
+			yz.A = utils.Cal_Point_Add(yz.A, coordinator.statement.Gen_u)
+		case "reveal":
+			yz.Commitments = append([]Party_Commitment{}, yz.Commitments...)
+			yz.Commitments[0].B = utils.Cal_Point_Add(yz.Commitments[0].B, coordinator.statement.Gen_u)
+		case "missing":
+			yz.Commitments = yz.Commitments[1:]
+		}
+		if _, err := parties[0].CommitT(yz); err == nil {
+			t.Errorf("challenge with a forged %s accepted", name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package any_proofs
+
+import (
+	"testing"
+
+	"anyOutOfMany/utils"
+)
+
+// Copy of a point vector with the i-th point replaced by a fresh one
+func replacePoint(points []utils.Point, i int) []utils.Point {
+	res := append([]utils.Point{}, points...)
+	res[i] = utils.GeneratePoint()
+	return res
+}
+
+// Changing any field of the statement changes the deterministic nonces of the same witness
+func TestNonceBindsStatement(t *testing.T) {
+	params, _, _, _, _ := testRing(8, nil)
+	var base Prover
+	base.New(params.Public_ck, utils.GeneratePoint(), params.Gen_u, params.Gen_v, params.Gen_Vec_G, params.Gen_Vec_H, 2, 8)
+	first := func(prover Prover) string {
+		prover.Deterministic([]byte("aux"))
+		prover.bindNonces()
+		return prover.s_0[0].String()
+	}
+	nonce := first(base)
+	if first(base) != nonce {
+		t.Fatal("the nonces are not deterministic")
+	}
+
+	mutations := map[string]func(prover *Prover){
+		"ck":   func(prover *Prover) { prover.Public_ck = utils.GeneratePoint() },
+		"u":    func(prover *Prover) { prover.Gen_u = utils.GeneratePoint() },
+		"v":    func(prover *Prover) { prover.Gen_v = utils.GeneratePoint() },
+		"G":    func(prover *Prover) { prover.Gen_Vec_G = replacePoint(prover.Gen_Vec_G, 7) },
+		"H":    func(prover *Prover) { prover.Gen_Vec_H = replacePoint(prover.Gen_Vec_H, 0) },
+		"ring": func(prover *Prover) { prover.Pub_Vec_Key = replacePoint(prover.Pub_Vec_Key, 3) },
+	}
+	for field, mutate := range mutations {
+		prover := base
+		mutate(&prover)
+		if first(prover) == nonce {
+			t.Errorf("the nonces do not depend on %s", field)
+		}
+	}
+}
+
+// The constrained provers hedge their nonces with fresh randomness: proofs of the same witness differ and verify
+func TestHedgedNonces(t *testing.T) {
+	params, ring, secrets, _, _ := testRing(8, []int{1, 6})
+	var commitments []utils.Point
+	for run := 0; run < 2; run++ {
+		statement, proof, err := params.ExactCountProve(ring, secrets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ExactCountVerify(statement, proof); err != nil {
+			t.Fatalf("honest proof rejected: %v", err)
+		}
+		commitments = append(commitments, proof.B)
+	}
+	if utils.Is_Equal_Point(commitments[0], commitments[1]) {
+		t.Fatal("hedged nonces repeat between runs")
+	}
+}
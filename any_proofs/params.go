@@ -0,0 +1,73 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/range_proofs"
+	"anyOutOfMany/utils"
+)
+
+// Public parameters of any-out-of-many proofs, a ring of N keys uses the first N generators of each vector
+type Params struct {
+	Public_ck            utils.Point   // generator as commitment key for public keys
+	Gen_u, Gen_v         utils.Point   // generators u,v
+	Gen_Vec_G, Gen_Vec_H []utils.Point // generator vector g h
+}
+
+// Initialization function
+func (params *Params) New(Public_ck utils.Point, U utils.Point, V utils.Point, G_Vector []utils.Point, H_Vector []utils.Point) {
+	params.Public_ck = Public_ck
+	params.Gen_u = U
+	params.Gen_v = V
+	params.Gen_Vec_G = G_Vector
+	params.Gen_Vec_H = H_Vector
+}
+
+// Generate public parameters serving rings of up to max_N keys, the generator vectors are at least 64 long
+// so that the range proofs over v,u of the threshold modes can share them
+func (params *Params) Setup(max_N int) {
+	n := max_N
+	if n < 64 {
+		n = 64
+	}
+	params.New(utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint(), utils.GenerateMultiPoint(n), utils.GenerateMultiPoint(n))
+}
+
+// Build the statement of a ring of public keys
+func (params *Params) Statement(ring []utils.Point) Statement {
+	return Statement{
+		Public_ck:   params.Public_ck,
+		Gen_u:       params.Gen_u,
+		Gen_v:       params.Gen_v,
+		Gen_Vec_G:   params.Gen_Vec_G,
+		Gen_Vec_H:   params.Gen_Vec_H,
+		Pub_Vec_Key: ring,
+	}
+}
+
+////////////////////////Private functions
+
+// Check that the generator vectors can serve a ring of N keys
+func (params *Params) checkRing(ring []utils.Point) error {
+	N := len(ring)
+	if N == 0 {
+		return errors.New("the ring set should not be empty")
+	}
+	if len(params.Gen_Vec_G) < N || len(params.Gen_Vec_H) < N {
+		return errors.New("the generator vectors are shorter than the ring set")
+	}
+	return nil
+}
+
+// Range proof parameters over the generators v,u sharing the generator vectors of the ring proof
+func rangeParams(statement Statement) range_proofs.Params {
+	var params range_proofs.Params
+	params.New(statement.Gen_v, statement.Gen_u, statement.Gen_Vec_G, statement.Gen_Vec_H)
+	return params
+}
+
+// Sample a random element of Zp
+func randomScalar() *big.Int {
+	return utils.Add_In_P(utils.Generate_Random_Zp(q), big.NewInt(0))
+}
@@ -36,6 +36,9 @@ type Prover struct {
 	alpha *big.Int
 	beta  *big.Int
 
+	// source of the nonces, crypto/rand if nil
+	nonces *utils.Nonce_Source
+
 	// intemediate values zeta, eta, t1, t2 and corresponding randomness
 	t_1, t_2 *big.Int
 	tau_1    *big.Int
@@ -73,22 +76,34 @@ func (prover *Prover) New(Public_ck utils.Point, G utils.Point, U utils.Point, V
 
 ////////////////////////Public interfaces
 
+//Derive the nonces deterministically from the secrets, the statement and the optional extra randomness aux
+func (prover *Prover) Deterministic(aux []byte) {
+	prover.nonces = utils.New_Nonce_Source(aux)
+}
+
 //Get response zeta, eta, t, tau_x, mu, f_s
 func (prover *Prover) GenerateRsp() ([]utils.Point, Transcript) {
+	transcript, _ := prover.generateRsp()
+	return prover.Pub_Vec_Key, transcript
+}
 
-	//prover computes Commitments A, B
+//Round 1: compute the commitments A, B
+func (prover *Prover) CommitAB() (utils.Point, utils.Point) {
 	prover.calculateAB()
+	return prover.A, prover.B
+}
 
-	//prover generates challenges y,z
-	prover.y, prover.z = Generate_YZ(prover.A, prover.B)
-
-	//prover compute Commitments T1, T2
+//Round 2: compute the commitments T1, T2, E for the challenges y, z
+func (prover *Prover) CommitT(y *big.Int, z *big.Int) (utils.Point, utils.Point, utils.Point) {
+	prover.y, prover.z = y, z
 	prover.calculateT()
 	prover.calculateE()
+	return prover.T1, prover.T2, prover.E
+}
 
-	//verifier get T1, T2, E and transmit x to prover
-	prover.x = Generate_X(prover.T1, prover.T2, prover.E)
-
+//Round 3: compute the responses for the challenge x, the prover may be rewound by calling it again with another x
+func (prover *Prover) Respond(x *big.Int) Transcript {
+	prover.x = x
 	prover.calculateLx()
 	prover.calculateRx()
 	prover.calculateIP()
@@ -96,8 +111,7 @@ func (prover *Prover) GenerateRsp() ([]utils.Point, Transcript) {
 	prover.calculateTaux()
 	prover.calculateFs()
 
-	//prover.TestCorrect()
-	transcript := Transcript{
+	return Transcript{
 		A:     prover.A,
 		B:     prover.B,
 		T1:    prover.T1,
@@ -113,11 +127,65 @@ func (prover *Prover) GenerateRsp() ([]utils.Point, Transcript) {
 		Y:     prover.y,
 		Z:     prover.z,
 	}
-	return prover.Pub_Vec_Key, transcript
+}
+
+//Get a non-interactive proof whose openings zeta, eta are compressed by the inner-product argument
+func (prover *Prover) GenerateProof() (Statement, Proof) {
+	trans, fs := prover.generateRsp()
+	pub_Vec_Key := prover.Pub_Vec_Key
+
+	// Compress the openings over generators g \circ P^{y^N} and h^{y^{-N}}
+	Inv_yN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(prover.y), prover.N)
+	Inv_Vec_H := utils.Generate_Point_Vector_with_y(prover.Gen_Vec_H[:prover.N], Inv_yN)
+	Pub_Key_yN := utils.Generate_Point_Vector_with_y(pub_Vec_Key, prover.YN)
+	Vec_G_P := utils.Cal_Point_Add_Vec(prover.Gen_Vec_G[:prover.N], Pub_Key_yN)
+	L, R, C_zeta, C_eta := utils.Opt_Prove(fs, trans.Zeta, trans.Eta, Vec_G_P, Inv_Vec_H, prover.Gen_v)
+
+	statement := prover.statement()
+	proof := Proof{
+		A:      trans.A,
+		B:      trans.B,
+		T1:     trans.T1,
+		T2:     trans.T2,
+		E:      trans.E,
+		Tau_x:  trans.Tau_x,
+		Mu:     trans.Mu,
+		Ip:     trans.Ip,
+		F_s:    trans.F_s,
+		L:      L,
+		R:      R,
+		C_zeta: C_zeta[0],
+		C_eta:  C_eta[0],
+	}
+	return statement, proof
 }
 
 ////////////////////////Private functions
 
+//Run the three rounds with Fiat-Shamir, returning the transcript state after the challenge x
+func (prover *Prover) generateRsp() (Transcript, *utils.Fiat_Shamir) {
+	prover.bindNonces()
+	statement := prover.statement()
+	fs := statement.newTranscript("plain")
+
+	//prover computes Commitments A, B
+	A, B := prover.CommitAB()
+
+	//prover generates challenges y,z and computes Commitments T1, T2, E
+	fs.Append_Point("A", A)
+	fs.Append_Point("B", B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+	T1, T2, E := prover.CommitT(y, z)
+
+	//prover generates challenge x and computes the responses
+	fs.Append_Point("T1", T1)
+	fs.Append_Point("T2", T2)
+	fs.Append_Point("E", E)
+	transcript := prover.Respond(fs.Challenge("x"))
+	return transcript, fs
+}
+
 //Generate b_0,b_1,s_0,s_1 and public keys
 func (prover *Prover) generateKey() {
 	//Generate binary vector b_0 b_1
@@ -129,16 +197,39 @@ func (prover *Prover) generateKey() {
 	prover.Pub_Vec_Key = prover.Generate_Multi_Public_Key(prover.Public_ck, prover.k, prover.N, prover.b_0) //generate public key vector Y
 }
 
+//Public statement of the prover
+func (prover *Prover) statement() Statement {
+	return Statement{
+		Public_ck:   prover.Public_ck,
+		Gen_u:       prover.Gen_u,
+		Gen_v:       prover.Gen_v,
+		Gen_Vec_G:   prover.Gen_Vec_G,
+		Gen_Vec_H:   prover.Gen_Vec_H,
+		Pub_Vec_Key: prover.Pub_Vec_Key,
+	}
+}
+
+//Restart the nonces from the secrets and the statement and rederive s_0, s_1
+func (prover *Prover) bindNonces() {
+	if prover.nonces == nil {
+		return
+	}
+	statement := prover.statement()
+	prover.nonces.Bind(*statement.newTranscript("nonces"), append(append([]*big.Int{}, prover.b_0...), prover.sec_Vec_Key...))
+	prover.s_0 = prover.nonces.Scalar_Vector(prover.N)
+	prover.s_1 = prover.nonces.Scalar_Vector(prover.N)
+}
+
 //Generate Commitments A,B,C,D
 func (prover *Prover) calculateAB() {
 	//Generate commitment A
-	prover.alpha = utils.Generate_Random_Zp(q)
+	prover.alpha = prover.nonces.Scalar()
 	g_b0_h_b1 := utils.Pedersen_Commit_Vector(prover.Gen_Vec_G, prover.Gen_Vec_H, prover.b_0, prover.b_1)
 	u_alpha := utils.Commit(prover.Gen_u, prover.alpha)
 	prover.A.X, prover.A.Y = utils.Curve.Add(g_b0_h_b1.X, g_b0_h_b1.Y, u_alpha.X, u_alpha.Y)
 
 	//Generate commitment B
-	prover.beta = utils.Generate_Random_Zp(q)
+	prover.beta = prover.nonces.Scalar()
 	g_s0_h_s1 := utils.Pedersen_Commit_Vector(prover.Gen_Vec_G, prover.Gen_Vec_H, prover.s_0, prover.s_1)
 	u_beta := utils.Commit(prover.Gen_u, prover.beta)
 	prover.B.X, prover.B.Y = utils.Curve.Add(g_s0_h_s1.X, g_s0_h_s1.Y, u_beta.X, u_beta.Y)
@@ -163,7 +254,7 @@ func (prover *Prover) calculateT() {
 	prover.t_1 = utils.Add_In_P(s0_yN_z_1N_b1, b0_z_1N_s1_yN)
 
 	//Generate tau2
-	prover.tau_1 = utils.Generate_Random_Zp(q)
+	prover.tau_1 = prover.nonces.Scalar()
 
 	//Compute T1
 	prover.T1 = utils.Pedersen_Commit(prover.Gen_v, prover.Gen_u, prover.t_1, prover.tau_1)
@@ -172,7 +263,7 @@ func (prover *Prover) calculateT() {
 	prover.t_2 = utils.Cal_IP_Vec(s0_yN, prover.s_1)
 
 	//Generate tau2
-	prover.tau_2 = utils.Generate_Random_Zp(q)
+	prover.tau_2 = prover.nonces.Scalar()
 
 	//Compute T2
 	prover.T2 = utils.Pedersen_Commit(prover.Gen_v, prover.Gen_u, prover.t_2, prover.tau_2)
@@ -180,7 +271,7 @@ func (prover *Prover) calculateT() {
 
 //Compute commitment E
 func (prover *Prover) calculateE() {
-	prover.r_s = utils.Generate_Random_Zp(q)
+	prover.r_s = prover.nonces.Scalar()
 	yN_s0 := utils.Cal_HP_Vec(prover.YN, prover.s_0)
 	P_yN_s0 := utils.Commit_Vector(prover.Pub_Vec_Key, yN_s0)
 	com_rs := utils.Commit(prover.Public_ck, utils.Neg_Zp(prover.r_s))
@@ -0,0 +1,141 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Verifier challenges of an interactive any-out-of-many proof
+type Challenges struct {
+	Y, Z, X *big.Int
+}
+
+// Simulate an interactive transcript for the given challenges without any witness. The responses zeta, eta, tau_x,
+// mu, f_s and the commitments A, T2 are uniform as in a real transcript, and B, E, T1 are the unique values that
+// satisfy the verification equations, so simulated and real transcripts have the same distribution
+func Simulate(statement Statement, challenges Challenges) Transcript {
+	N := len(statement.Pub_Vec_Key)
+	y, z, x := challenges.Y, challenges.Z, challenges.X
+	inv_x := utils.Inverse_Zp(x)
+	YN := Generate_Exp_Scalar_Vector(y, N)
+	Inv_YN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), N)
+	z1N := Generate_Scalar_Vector(z, N)
+
+	zeta := randomVector(N)
+	eta := randomVector(N)
+	tau_x := randomScalar()
+	mu := randomScalar()
+	f_s := randomScalar()
+	A := utils.Commit(statement.Gen_u, randomScalar())
+	T2 := utils.Commit(statement.Gen_u, randomScalar())
+	ip := utils.Cal_IP_Vec(zeta, eta)
+
+	// T1 = (v^{ip - delta} u^{tau_x} T2^{-x^2})^{1/x}
+	delta := transcriptDelta(y, z, N)
+	T1 := utils.Multi_Scalar_Mult([]utils.Point{statement.Gen_v, statement.Gen_u, T2},
+		[]*big.Int{utils.Sub_In_P(ip, delta), tau_x, utils.Neg_Zp(utils.Mul_In_P(x, x))})
+	T1 = utils.Cal_Point_Sca(T1, inv_x)
+
+	// B = (g^{zeta - z} h^{y^{-N} \circ eta - z} u^{mu} A^{-1})^{1/x}
+	var points []utils.Point
+	var scalars []*big.Int
+	points = append(points, statement.Gen_Vec_G[:N]...)
+	scalars = append(scalars, utils.Cal_Sub_Vec(zeta, z1N)...)
+	points = append(points, statement.Gen_Vec_H[:N]...)
+	scalars = append(scalars, utils.Cal_Sub_Vec(utils.Cal_HP_Vec(Inv_YN, eta), z1N)...)
+	points = append(points, statement.Gen_u, A)
+	scalars = append(scalars, mu, utils.Neg_Zp(big.NewInt(1)))
+	B := utils.Cal_Point_Sca(utils.Multi_Scalar_Mult(points, scalars), inv_x)
+
+	// E = (P^{y^N \circ (zeta - z)} ck^{-f_s})^{1/x}
+	points = append(append([]utils.Point{}, statement.Pub_Vec_Key...), statement.Public_ck)
+	scalars = append(utils.Cal_HP_Vec(YN, utils.Cal_Sub_Vec(zeta, z1N)), utils.Neg_Zp(f_s))
+	E := utils.Cal_Point_Sca(utils.Multi_Scalar_Mult(points, scalars), inv_x)
+
+	return Transcript{
+		A:     A,
+		B:     B,
+		T1:    T1,
+		T2:    T2,
+		E:     E,
+		Tau_x: tau_x,
+		Mu:    mu,
+		Ip:    ip,
+		Zeta:  zeta,
+		Eta:   eta,
+		F_s:   f_s,
+		X:     x,
+		Y:     y,
+		Z:     z,
+	}
+}
+
+// Check an interactive transcript with uncompressed openings against the statement:
+// (1) v^{ip} u^{tau_x} = v^{delta} T1^x T2^{x^2} with ip = <zeta, eta>
+// (2) g^{zeta} h^{y^{-N} \circ eta} = A B^x g^z h^z u^{-mu}
+// (3) P^{y^N \circ zeta} = ck^{f_s} E^x P^{z y^N}
+func CheckTranscript(statement Statement, trans Transcript) error {
+	N := len(statement.Pub_Vec_Key)
+	if N == 0 || len(statement.Gen_Vec_G) < N || len(statement.Gen_Vec_H) < N || len(trans.Zeta) != N || len(trans.Eta) != N {
+		return errors.New("the openings should be as long as the ring set")
+	}
+	for _, c := range []*big.Int{trans.X, trans.Y, trans.Z} {
+		if !utils.Is_Valid_Scalar(c) || c.Sign() == 0 {
+			return errors.New("the challenges should be non-zero elements of Zp")
+		}
+	}
+	y, z, x := trans.Y, trans.Z, trans.X
+	YN := Generate_Exp_Scalar_Vector(y, N)
+	Inv_YN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), N)
+	z1N := Generate_Scalar_Vector(z, N)
+	if utils.Cal_IP_Vec(trans.Zeta, trans.Eta).Cmp(trans.Ip) != 0 {
+		return errors.New("the inner product does not match the openings")
+	}
+
+	var me utils.Multi_Exp
+	me.Add_Term(statement.Gen_v, utils.Sub_In_P(trans.Ip, transcriptDelta(y, z, N)))
+	me.Add_Term(statement.Gen_u, trans.Tau_x)
+	me.Add_Term(trans.T1, utils.Neg_Zp(x))
+	me.Add_Term(trans.T2, utils.Neg_Zp(utils.Mul_In_P(x, x)))
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("the transcript fails the inner-product equation")
+	}
+
+	me = utils.Multi_Exp{}
+	me.Add_Terms(statement.Gen_Vec_G[:N], utils.Cal_Sub_Vec(trans.Zeta, z1N))
+	me.Add_Terms(statement.Gen_Vec_H[:N], utils.Cal_Sub_Vec(utils.Cal_HP_Vec(Inv_YN, trans.Eta), z1N))
+	me.Add_Term(statement.Gen_u, trans.Mu)
+	me.Add_Term(trans.A, utils.Neg_Zp(big.NewInt(1)))
+	me.Add_Term(trans.B, utils.Neg_Zp(x))
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("the transcript fails the opening equation")
+	}
+
+	me = utils.Multi_Exp{}
+	me.Add_Terms(statement.Pub_Vec_Key, utils.Cal_HP_Vec(YN, utils.Cal_Sub_Vec(trans.Zeta, z1N)))
+	me.Add_Term(statement.Public_ck, utils.Neg_Zp(trans.F_s))
+	me.Add_Term(trans.E, utils.Neg_Zp(x))
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("the transcript fails the secret key equation")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// delta = (z + z^2) <1^N, y^N>
+func transcriptDelta(y *big.Int, z *big.Int, N int) *big.Int {
+	sum_yN := utils.Cal_IP_Vec(Generate_Scalar_Vector(big.NewInt(1), N), Generate_Exp_Scalar_Vector(y, N))
+	return utils.Mul_In_P(utils.Add_In_P(z, utils.Mul_In_P(z, z)), sum_yN)
+}
+
+// Draw n uniform elements of Zp
+func randomVector(n int) []*big.Int {
+	var res []*big.Int
+	for i := 0; i < n; i++ {
+		res = append(res, randomScalar())
+	}
+	return res
+}
@@ -0,0 +1,66 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/range_proofs"
+	"anyOutOfMany/utils"
+)
+
+// Public statement of a threshold proof: the ring and the public lower bound t on the number of known keys
+type ThresholdStatement struct {
+	Statement
+	Threshold int         // public threshold t
+	Pub_Count utils.Point // commitment W = v^{<b_0, 1>} u^gamma to the hidden number of known keys
+}
+
+// Threshold proof: a ring proof binding W to the hidden subset and a range proof on <b_0, 1> - t
+type ThresholdProof struct {
+	Proof
+	Range range_proofs.Proof
+}
+
+// Generate a proof that the prover knows at least t secret keys of the ring, without revealing which ones
+// or how many, the threshold mode is the weighted mode with unit weights
+func (params *Params) ThresholdProve(ring []utils.Point, secrets []*big.Int, t int) (ThresholdStatement, ThresholdProof, error) {
+	if t < 0 {
+		return ThresholdStatement{}, ThresholdProof{}, errors.New("the threshold should not be negative")
+	}
+	if len(secrets) < t {
+		return ThresholdStatement{}, ThresholdProof{}, errors.New("the number of secret keys is below the threshold")
+	}
+	statement, proof, err := params.WeightedProve(ring, secrets, unitWeights(len(ring)), uint64(t))
+	if err != nil {
+		return ThresholdStatement{}, ThresholdProof{}, err
+	}
+	return ThresholdStatement{Statement: statement.Statement, Threshold: t, Pub_Count: statement.Pub_Weight}, ThresholdProof(proof), nil
+}
+
+// Verify that the prover knows at least t secret keys of the ring
+func ThresholdVerify(statement ThresholdStatement, proof ThresholdProof) error {
+	if statement.Threshold < 0 {
+		return errors.New("the threshold should not be negative")
+	}
+	weighted := WeightedStatement{
+		Statement:  statement.Statement,
+		Weights:    unitWeights(len(statement.Pub_Vec_Key)),
+		Threshold:  uint64(statement.Threshold),
+		Pub_Weight: statement.Pub_Count,
+	}
+	if err := WeightedVerify(weighted, WeightedProof(proof)); err != nil {
+		return errors.New("invalid threshold proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// Weight 1 for each of the N ring keys
+func unitWeights(N int) []uint64 {
+	weights := make([]uint64, N)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
@@ -0,0 +1,51 @@
+package any_proofs
+
+import (
+	"testing"
+
+	"anyOutOfMany/utils"
+)
+
+func TestThreshold(t *testing.T) {
+	tests := []struct {
+		signers   []int
+		threshold int
+		accept    bool
+	}{
+		{[]int{1, 4, 6}, 3, true},
+		{[]int{1, 4, 6}, 1, true},
+		{[]int{3}, 0, true},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, 8, true},
+		{[]int{1, 4, 6}, 4, false},
+		{[]int{1}, -1, false},
+		{nil, 1, false},
+	}
+	for _, test := range tests {
+		params, ring, secrets, _, _ := testRing(8, test.signers)
+		statement, proof, err := params.ThresholdProve(ring, secrets, test.threshold)
+		if !test.accept {
+			if err == nil {
+				t.Errorf("%d keys >= %d: proof generated", len(test.signers), test.threshold)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d keys >= %d: %v", len(test.signers), test.threshold, err)
+		}
+		if err := ThresholdVerify(statement, proof); err != nil {
+			t.Errorf("%d keys >= %d: honest proof rejected: %v", len(test.signers), test.threshold, err)
+		}
+
+		// The proof neither covers a higher threshold nor a count commitment raised by one
+		raised := statement
+		raised.Threshold++
+		if ThresholdVerify(raised, proof) == nil {
+			t.Errorf("%d keys >= %d: proof accepted for a higher threshold", len(test.signers), test.threshold)
+		}
+		shifted := statement
+		shifted.Pub_Count = utils.Cal_Point_Add(statement.Pub_Count, statement.Gen_v)
+		if ThresholdVerify(shifted, proof) == nil {
+			t.Errorf("%d keys >= %d: proof accepted for a larger count", len(test.signers), test.threshold)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Public statement of a tuple ring: each member is a tuple P_i = (sk_i base_1, ..., sk_i base_m) over public bases
+type TupleStatement struct {
+	Gen_u, Gen_v         utils.Point     // generators u,v
+	Gen_Vec_G, Gen_Vec_H []utils.Point   // generator vector g h, at least N long
+	Bases                []utils.Point   // public bases base_1,...,base_m
+	Pub_Tuples           [][]utils.Point // ring of key tuples
+}
+
+// Generate a proof of knowledge of the common secret of some members of a tuple ring. E carries one blinding
+// r_k base_k per base and the response f_k of base_k answers for the secret times xi^k, where the tuples are
+// combined into the ring P_i = \sum_k xi^k P_{i,k} of the inner-product argument. The responses are tied by
+// f_k = xi^k f_s, so one secret opens every point of a selected tuple
+func (params *Params) TupleProve(bases []utils.Point, ring [][]utils.Point, secrets []*big.Int) (TupleStatement, Proof, error) {
+	statement := TupleStatement{
+		Gen_u:      params.Gen_u,
+		Gen_v:      params.Gen_v,
+		Gen_Vec_G:  params.Gen_Vec_G,
+		Gen_Vec_H:  params.Gen_Vec_H,
+		Bases:      bases,
+		Pub_Tuples: ring,
+	}
+	combined, xi, fs, err := statement.combine()
+	if err != nil {
+		return TupleStatement{}, Proof{}, err
+	}
+	if err := params.checkRing(combined.Pub_Vec_Key); err != nil {
+		return TupleStatement{}, Proof{}, err
+	}
+	b_0, sk, err := statement.selectTuples(secrets)
+	if err != nil {
+		return TupleStatement{}, Proof{}, err
+	}
+	r := hedgedNonces(fs, sk).Scalar()
+	var keys [][]*big.Int
+	var r_s []*big.Int
+	for k := range bases {
+		keys = append(keys, utils.Cal_Sca_Vec(sk, xi[k]))
+		r_s = append(r_s, utils.Mul_In_P(xi[k], r))
+	}
+	N := len(ring)
+	proof, _ := proveConstrainedBlinded(fs, combined, bases, r_s, b_0, keys, Generate_Scalar_Vector(big.NewInt(0), N), big.NewInt(0))
+	return statement, proof, nil
+}
+
+// Verify a tuple ring proof
+func TupleVerify(statement TupleStatement, proof Proof) error {
+	combined, xi, fs, err := statement.combine()
+	if err != nil {
+		return err
+	}
+	if !utils.Is_Valid_Scalar(proof.F_s) {
+		return errors.New("the proof contains an invalid scalar")
+	}
+	var f_extra []*big.Int
+	for k := 1; k < len(xi); k++ {
+		f_extra = append(f_extra, utils.Mul_In_P(xi[k], proof.F_s))
+	}
+	N := len(statement.Pub_Tuples)
+	if err := verifyConstrainedBases(fs, combined, statement.Bases, f_extra, Generate_Scalar_Vector(big.NewInt(0), N), big.NewInt(0), nil, proof); err != nil {
+		return errors.New("invalid tuple ring proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// Check that a tuple statement is well formed: every tuple has one valid point per base and the generator
+// vectors are at least as long as the ring set
+func (statement *TupleStatement) checkFormat() error {
+	m := len(statement.Bases)
+	N := len(statement.Pub_Tuples)
+	if m == 0 || N == 0 {
+		return errors.New("the bases and the ring set should not be empty")
+	}
+	if len(statement.Gen_Vec_G) < N || len(statement.Gen_Vec_H) < N {
+		return errors.New("the generator vectors should be at least as long as the ring set")
+	}
+	points := []utils.Point{statement.Gen_u, statement.Gen_v}
+	points = append(points, statement.Bases...)
+	for _, tuple := range statement.Pub_Tuples {
+		if len(tuple) != m {
+			return errors.New("every tuple should have one point per base")
+		}
+		points = append(points, tuple...)
+	}
+	for _, point := range points {
+		if !utils.Is_Valid_Point(point) {
+			return errors.New("the statement contains an invalid point")
+		}
+	}
+	return nil
+}
+
+// Combine the tuples with the powers (1, xi, ..., xi^{m-1}) of a challenge into the ring of an ordinary statement
+// whose commitment key is base_1, and return the powers and the transcript
+func (statement *TupleStatement) combine() (Statement, []*big.Int, *utils.Fiat_Shamir, error) {
+	if err := statement.checkFormat(); err != nil {
+		return Statement{}, nil, nil, err
+	}
+	m := len(statement.Bases)
+	var fs utils.Fiat_Shamir
+	fs.New("any_proofs")
+	fs.Append_Bytes("mode", []byte("tuple"))
+	fs.Append_Point("u", statement.Gen_u)
+	fs.Append_Point("v", statement.Gen_v)
+	fs.Append_Points("bases", statement.Bases)
+	for _, tuple := range statement.Pub_Tuples {
+		fs.Append_Points("P", tuple)
+	}
+	xi := Generate_Exp_Scalar_Vector(fs.Challenge("xi"), m)
+
+	combined := Statement{
+		Public_ck: statement.Bases[0],
+		Gen_u:     statement.Gen_u,
+		Gen_v:     statement.Gen_v,
+		Gen_Vec_G: statement.Gen_Vec_G,
+		Gen_Vec_H: statement.Gen_Vec_H,
+	}
+	for _, tuple := range statement.Pub_Tuples {
+		combined.Pub_Vec_Key = append(combined.Pub_Vec_Key, utils.Multi_Scalar_Mult(tuple, xi))
+	}
+	N := len(combined.Pub_Vec_Key)
+	fs.Append_Points("G", utils.Point_Prefix(statement.Gen_Vec_G, N))
+	fs.Append_Points("H", utils.Point_Prefix(statement.Gen_Vec_H, N))
+	return combined, xi, &fs, nil
+}
+
+// Select one distinct tuple per secret key, returning the selector and the secrets indexed by ring position
+func (statement *TupleStatement) selectTuples(secrets []*big.Int) ([]*big.Int, []*big.Int, error) {
+	N := len(statement.Pub_Tuples)
+	b_0 := Generate_Scalar_Vector(big.NewInt(0), N)
+	sk := Generate_Scalar_Vector(big.NewInt(0), N)
+	for _, secret := range secrets {
+		if !utils.Is_Valid_Scalar(secret) || secret.Sign() == 0 {
+			return nil, nil, errors.New("the secret keys should be non-zero elements of Zp")
+		}
+		found := false
+		for i := range statement.Pub_Tuples {
+			if b_0[i].Sign() == 0 && statement.tupleMatches(i, secret) {
+				b_0[i] = big.NewInt(1)
+				sk[i] = secret
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, nil, errors.New("a secret key does not open every point of a tuple of the ring set")
+		}
+	}
+	return b_0, sk, nil
+}
+
+// Check that sk opens every point of the i-th tuple
+func (statement *TupleStatement) tupleMatches(i int, sk *big.Int) bool {
+	for k, base := range statement.Bases {
+		if !utils.Is_Equal_Point(utils.Commit(base, sk), statement.Pub_Tuples[i][k]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package any_proofs
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+)
+
+// Ring of N key pairs (sk g_1, sk g_2) whose secrets at the positions in signers are returned
+func testTuples(params Params, bases []utils.Point, N int, signers []int) ([][]utils.Point, []*big.Int) {
+	ring := make([][]utils.Point, N)
+	var secrets []*big.Int
+	for i := range ring {
+		sk := randomScalar()
+		for _, base := range bases {
+			ring[i] = append(ring[i], utils.Commit(base, sk))
+		}
+		for _, j := range signers {
+			if i == j {
+				secrets = append(secrets, sk)
+			}
+		}
+	}
+	return ring, secrets
+}
+
+func TestTuple(t *testing.T) {
+	params, _, _, _, _ := testRing(8, nil)
+	bases := []utils.Point{params.Public_ck, utils.GeneratePoint(), utils.GeneratePoint()}
+	ring, secrets := testTuples(params, bases, 8, []int{0, 6})
+	statement, proof, err := params.TupleProve(bases, ring, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := TupleVerify(statement, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+
+	// The proof is bound to every point of the tuples
+	statement.Pub_Tuples[5][1] = utils.Cal_Point_Add(statement.Pub_Tuples[5][1], bases[1])
+	if err := TupleVerify(statement, proof); err == nil {
+		t.Fatal("proof accepted for another ring")
+	}
+
+	// A key that opens only some points of a tuple is not accepted
+	sk := randomScalar()
+	ring[3] = []utils.Point{utils.Commit(bases[0], sk), utils.Commit(bases[1], sk), utils.Commit(bases[2], randomScalar())}
+	if _, _, err := params.TupleProve(bases, ring, []*big.Int{sk}); err == nil {
+		t.Fatal("proof for a malformed tuple generated")
+	}
+}
+
+// A member (sk g_1, sk' g_2) has a representation over the bases, but the per-base responses answer for two
+// different secrets and fail the tie f_2 = xi f_1
+func TestTupleMixedSecrets(t *testing.T) {
+	params, _, _, _, _ := testRing(4, nil)
+	bases := []utils.Point{params.Public_ck, utils.GeneratePoint()}
+	ring, _ := testTuples(params, bases, 4, nil)
+	sk, sk_2 := randomScalar(), randomScalar()
+	ring[2] = []utils.Point{utils.Commit(bases[0], sk), utils.Commit(bases[1], sk_2)}
+	statement := TupleStatement{
+		Gen_u:      params.Gen_u,
+		Gen_v:      params.Gen_v,
+		Gen_Vec_G:  params.Gen_Vec_G,
+		Gen_Vec_H:  params.Gen_Vec_H,
+		Bases:      bases,
+		Pub_Tuples: ring,
+	}
+	combined, xi, fs, err := statement.combine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b_0 := Generate_Scalar_Vector(big.NewInt(0), 4)
+	b_0[2] = big.NewInt(1)
+	keys := [][]*big.Int{Generate_Scalar_Vector(big.NewInt(0), 4), Generate_Scalar_Vector(big.NewInt(0), 4)}
+	keys[0][2] = sk
+	keys[1][2] = utils.Mul_In_P(xi[1], sk_2)
+	zero := Generate_Scalar_Vector(big.NewInt(0), 4)
+	proof, _ := proveConstrainedBases(fs, combined, bases, b_0, keys, zero, big.NewInt(0))
+	if err := TupleVerify(statement, proof); err == nil {
+		t.Fatal("proof for a tuple of two secrets accepted")
+	}
+}
+
+// Malformed tuple statements are rejected before the tuples are combined
+func TestTupleFormat(t *testing.T) {
+	params, _, _, _, _ := testRing(8, nil)
+	bases := []utils.Point{params.Public_ck, utils.GeneratePoint()}
+	ring, secrets := testTuples(params, bases, 8, []int{4})
+	statement, proof, err := params.TupleProve(bases, ring, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	off := utils.Point{X: new(big.Int).Set(ring[1][1].X), Y: utils.Add_In_P(ring[1][1].Y, big.NewInt(1))}
+	mutations := map[string]func(statement *TupleStatement){
+		"off-curve point": func(statement *TupleStatement) { statement.Pub_Tuples[1][1] = off },
+		"nil point":       func(statement *TupleStatement) { statement.Pub_Tuples[6][0] = utils.Point{} },
+		"identity": func(statement *TupleStatement) {
+			statement.Pub_Tuples[0][0] = utils.Point{X: big.NewInt(0), Y: big.NewInt(0)}
+		},
+		"short tuple":  func(statement *TupleStatement) { statement.Pub_Tuples[2] = statement.Pub_Tuples[2][:1] },
+		"long tuple":   func(statement *TupleStatement) { statement.Pub_Tuples[3] = append(statement.Pub_Tuples[3], bases[0]) },
+		"invalid base": func(statement *TupleStatement) { statement.Bases = []utils.Point{bases[0], off} },
+		"short G":      func(statement *TupleStatement) { statement.Gen_Vec_G = statement.Gen_Vec_G[:7] },
+		"empty ring":   func(statement *TupleStatement) { statement.Pub_Tuples = nil },
+	}
+	for name, mutate := range mutations {
+		malformed := statement
+		malformed.Pub_Tuples = make([][]utils.Point, len(statement.Pub_Tuples))
+		for i := range statement.Pub_Tuples {
+			malformed.Pub_Tuples[i] = append([]utils.Point{}, statement.Pub_Tuples[i]...)
+		}
+		mutate(&malformed)
+		if err := TupleVerify(malformed, proof); err == nil {
+			t.Errorf("proof accepted for a statement with %s", name)
+		}
+		if _, _, err := params.TupleProve(malformed.Bases, malformed.Pub_Tuples, secrets); err == nil && name != "short G" {
+			t.Errorf("proof generated for a ring with %s", name)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package any_proofs
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"anyOutOfMany/range_proofs"
+	"anyOutOfMany/utils"
+)
+
+// Public statement of a weighted threshold proof: the ring with public weights w_i and the threshold T
+type WeightedStatement struct {
+	Statement
+	Weights    []uint64    // public weight w_i of every ring key
+	Threshold  uint64      // threshold T on the total weight
+	Pub_Weight utils.Point // commitment W = v^{<b_0, w>} u^gamma to the total weight of the subset
+}
+
+// Weighted threshold proof: a ring proof binding W to the hidden subset and a range proof on <b_0, w> - T
+type WeightedProof struct {
+	Proof
+	Range range_proofs.Proof
+}
+
+// Generate a proof that the prover knows the secret keys of a subset of the ring whose total weight is at least
+// the threshold, without revealing the subset or its weight
+func (params *Params) WeightedProve(ring []utils.Point, secrets []*big.Int, weights []uint64, threshold uint64) (WeightedStatement, WeightedProof, error) {
+	if err := params.checkRing(ring); err != nil {
+		return WeightedStatement{}, WeightedProof{}, err
+	}
+	total, err := totalWeight(weights, len(ring))
+	if err != nil {
+		return WeightedStatement{}, WeightedProof{}, err
+	}
+	if threshold > total {
+		return WeightedStatement{}, WeightedProof{}, errors.New("the threshold exceeds the total weight of the ring")
+	}
+	b_0, keys, err := Generate_Selected_b_0(params.Public_ck, ring, secrets)
+	if err != nil {
+		return WeightedStatement{}, WeightedProof{}, err
+	}
+	var omega uint64
+	for i := range b_0 {
+		if b_0[i].Sign() != 0 {
+			omega += weights[i]
+		}
+	}
+	if omega < threshold {
+		return WeightedStatement{}, WeightedProof{}, errors.New("the weight of the secret keys is below the threshold")
+	}
+	d := weightWidth(total, threshold)
+	if len(params.Gen_Vec_G) < d || len(params.Gen_Vec_H) < d {
+		return WeightedStatement{}, WeightedProof{}, errors.New("the generator vectors are shorter than the width of the total weight")
+	}
+
+	ring_statement := params.Statement(ring)
+	gamma := hedgedNonces(ring_statement.newTranscript("weighted"), b_0, keys).Scalar()
+	statement := WeightedStatement{
+		Statement:  ring_statement,
+		Weights:    weights,
+		Threshold:  threshold,
+		Pub_Weight: utils.Pedersen_Commit(params.Gen_v, params.Gen_u, new(big.Int).SetUint64(omega), gamma),
+	}
+	proof := proveConstrained(statement.newTranscript(), statement.Statement, b_0, keys, weightVector(weights), gamma)
+
+	// Range proof that W v^{-T} = v^{omega - T} u^gamma commits to a value in [0, 2^d)
+	rp_params := rangeParams(statement.Statement)
+	_, range_proof, err := rp_params.Prove(omega-threshold, gamma, d)
+	if err != nil {
+		return WeightedStatement{}, WeightedProof{}, err
+	}
+	return statement, WeightedProof{Proof: proof, Range: range_proof}, nil
+}
+
+// Verify a weighted threshold proof
+func WeightedVerify(statement WeightedStatement, proof WeightedProof) error {
+	total, err := totalWeight(statement.Weights, len(statement.Pub_Vec_Key))
+	if err != nil {
+		return err
+	}
+	if statement.Threshold > total {
+		return errors.New("the threshold exceeds the total weight of the ring")
+	}
+	if err := verifyConstrained(statement.newTranscript(), statement.Statement, weightVector(statement.Weights), big.NewInt(0), &statement.Pub_Weight, proof.Proof); err != nil {
+		return errors.New("invalid weighted threshold proof")
+	}
+	rp_params := rangeParams(statement.Statement)
+	shifted := utils.Multi_Scalar_Mult([]utils.Point{statement.Pub_Weight, statement.Gen_v}, []*big.Int{big.NewInt(1), utils.Neg_Zp(new(big.Int).SetUint64(statement.Threshold))})
+	rp_statement := rp_params.Statement([]utils.Point{shifted}, weightWidth(total, statement.Threshold))
+	if err := range_proofs.Verify(rp_statement, proof.Range); err != nil {
+		return errors.New("invalid weighted threshold proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// Start the transcript of a weighted statement, binding the weights, the threshold and W
+func (statement *WeightedStatement) newTranscript() *utils.Fiat_Shamir {
+	fs := statement.Statement.newTranscript("weighted")
+	for _, weight := range statement.Weights {
+		fs.Append_Scalar("w", new(big.Int).SetUint64(weight))
+	}
+	fs.Append_Scalar("T", new(big.Int).SetUint64(statement.Threshold))
+	fs.Append_Point("W", statement.Pub_Weight)
+	return fs
+}
+
+// Sum of the weights, which should be one per ring key and fit into 64 bits
+func totalWeight(weights []uint64, N int) (uint64, error) {
+	if len(weights) != N {
+		return 0, errors.New("every ring key should have a weight")
+	}
+	var total uint64
+	for _, weight := range weights {
+		sum, carry := bits.Add64(total, weight, 0)
+		if carry != 0 {
+			return 0, errors.New("the total weight should be smaller than 2^64")
+		}
+		total = sum
+	}
+	return total, nil
+}
+
+// Bit width of the range proof on <b_0, w> - T, which lies in [0, total - T]
+func weightWidth(total uint64, threshold uint64) int {
+	d := bits.Len64(total - threshold)
+	if d == 0 {
+		d = 1
+	}
+	return d
+}
+
+// Convert the weights into scalars
+func weightVector(weights []uint64) []*big.Int {
+	var w []*big.Int
+	for _, weight := range weights {
+		w = append(w, new(big.Int).SetUint64(weight))
+	}
+	return w
+}
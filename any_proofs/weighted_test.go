@@ -0,0 +1,81 @@
+package any_proofs
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+)
+
+func TestWeighted(t *testing.T) {
+	weights := []uint64{5, 1, 20, 3, 0, 7, 2, 40}
+	tests := []struct {
+		signers   []int
+		threshold uint64
+		accept    bool // whether the prover can reach the threshold
+	}{
+		{[]int{2, 5}, 27, true},
+		{[]int{2, 5}, 10, true},
+		{[]int{7}, 0, true},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, 78, true},
+		{[]int{2, 5}, 28, false},
+		{[]int{4}, 1, false},
+		{[]int{0}, 79, false},
+	}
+	for _, test := range tests {
+		params, ring, secrets, _, _ := testRing(8, test.signers)
+		statement, proof, err := params.WeightedProve(ring, secrets, weights, test.threshold)
+		if !test.accept {
+			if err == nil {
+				t.Errorf("%v >= %d: proof generated below the threshold", test.signers, test.threshold)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v >= %d: %v", test.signers, test.threshold, err)
+		}
+		if err := WeightedVerify(statement, proof); err != nil {
+			t.Errorf("%v >= %d: honest proof rejected: %v", test.signers, test.threshold, err)
+		}
+
+		// Raising the threshold or the weight of an unselected key breaks the proof
+		raised := statement
+		raised.Threshold = test.threshold + 1
+		if WeightedVerify(raised, proof) == nil {
+			t.Errorf("%v >= %d: proof accepted for a higher threshold", test.signers, test.threshold)
+		}
+		reweighted := statement
+		reweighted.Weights = append([]uint64{}, weights...)
+		reweighted.Weights[4]++
+		if WeightedVerify(reweighted, proof) == nil {
+			t.Errorf("%v >= %d: proof accepted for other weights", test.signers, test.threshold)
+		}
+	}
+}
+
+// A prover committing W to the threshold instead of the actual weight of its subset is rejected
+func TestWeightedWrongTotal(t *testing.T) {
+	weights := []uint64{5, 1, 20, 3, 0, 7, 2, 40}
+	params, ring, secrets, _, _ := testRing(8, []int{0, 1})
+	b_0, keys, err := Generate_Selected_b_0(params.Public_ck, ring, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	threshold := uint64(10) // the subset weighs 6
+	gamma := randomScalar()
+	statement := WeightedStatement{
+		Statement:  params.Statement(ring),
+		Weights:    weights,
+		Threshold:  threshold,
+		Pub_Weight: utils.Pedersen_Commit(params.Gen_v, params.Gen_u, new(big.Int).SetUint64(threshold), gamma),
+	}
+	proof := WeightedProof{Proof: proveConstrained(statement.newTranscript(), statement.Statement, b_0, keys, weightVector(weights), gamma)}
+	rp_params := rangeParams(statement.Statement)
+	_, proof.Range, err = rp_params.Prove(0, gamma, weightWidth(78, threshold))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if WeightedVerify(statement, proof) == nil {
+		t.Fatal("proof accepted with a wrong total weight")
+	}
+}
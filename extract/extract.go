@@ -0,0 +1,282 @@
+package extract
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/any_proofs"
+	"anyOutOfMany/range_proofs"
+	"anyOutOfMany/utils"
+)
+
+// Knowledge extractors for soundness testing. The extractor drives a round-based prover, rewinds it to the same
+// commitments and answers with several challenges, and solves the responses for the witness. It works on the
+// uncompressed transcripts of Respond, which carry the openings zeta, eta in full, so an error means the prover
+// answered those rounds without a valid witness. The inner-product argument compressing zeta, eta in the proofs
+// accepted by Verify is not rewound, and the extractor says nothing about its soundness
+
+// Round-based any-out-of-many prover, e.g., any_proofs.Prover
+type Ring_Prover interface {
+	CommitAB() (utils.Point, utils.Point)
+	CommitT(y *big.Int, z *big.Int) (utils.Point, utils.Point, utils.Point)
+	Respond(x *big.Int) any_proofs.Transcript
+}
+
+// Round-based range prover, e.g., range_proofs.Prover
+type Range_Prover interface {
+	CommitAB() (utils.Point, utils.Point)
+	CommitT(y *big.Int, z *big.Int) (utils.Point, utils.Point)
+	Respond(x *big.Int) range_proofs.Transcript
+}
+
+// Witness of an any-out-of-many proof
+type Ring_Witness struct {
+	B_0  []*big.Int // selector vector
+	Keys []*big.Int // secret keys at the selected positions, zero elsewhere
+}
+
+// Witness of a range proof
+type Range_Witness struct {
+	B_0   []*big.Int // bits of the value
+	Value *big.Int
+	Gamma *big.Int // blinding factor
+}
+
+// Extract the selector and the secret keys from an any-out-of-many prover. Rewinding x twice after the same
+// (A, B, T1, T2, E) gives b_0 and the combination \sum_i y^i b0_i sk_i, and rewinding y once per selected key
+// separates the keys
+func AnyProofs(prover Ring_Prover, statement any_proofs.Statement) (Ring_Witness, error) {
+	N := len(statement.Pub_Vec_Key)
+	A, B := prover.CommitAB()
+
+	var witness Ring_Witness
+	var selected []int
+	var rows [][]*big.Int
+	var combinations []*big.Int
+	for fork := 0; fork == 0 || fork < len(selected); fork++ {
+		y, z := challenge(), challenge()
+		prover.CommitT(y, z)
+		trans, err := ringResponses(prover, statement, A, B, 2)
+		if err != nil {
+			return Ring_Witness{}, err
+		}
+
+		// zeta(x) = b0 + z + s0 x and y^{-N} \circ eta(x) = b1 + z + s1 x with b1 = 1 - b0
+		Inv_YN := any_proofs.Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), N)
+		b_0 := linearConstant(trans[0].X, trans[1].X, trans[0].Zeta, trans[1].Zeta)
+		b_1 := linearConstant(trans[0].X, trans[1].X, utils.Cal_HP_Vec(Inv_YN, trans[0].Eta), utils.Cal_HP_Vec(Inv_YN, trans[1].Eta))
+		for i := 0; i < N; i++ {
+			b_0[i] = utils.Sub_In_P(b_0[i], z)
+			b_1[i] = utils.Sub_In_P(b_1[i], z)
+			if !isBit(b_0[i]) || utils.Sub_In_P(big.NewInt(1), b_0[i]).Cmp(b_1[i]) != 0 {
+				return Ring_Witness{}, errors.New("the extracted selector is not binary")
+			}
+		}
+		if fork == 0 {
+			witness.B_0 = b_0
+			for i := range b_0 {
+				if b_0[i].Sign() != 0 {
+					selected = append(selected, i)
+				}
+			}
+			if len(selected) == 0 {
+				return Ring_Witness{}, errors.New("the extracted selector is zero")
+			}
+		} else if !equalVectors(b_0, witness.B_0) {
+			return Ring_Witness{}, errors.New("the prover opened A to two selectors")
+		}
+
+		// f_s(x) = r_s x + \sum_i y^i b0_i sk_i
+		YN := any_proofs.Generate_Exp_Scalar_Vector(y, N)
+		var row []*big.Int
+		for _, i := range selected {
+			row = append(row, YN[i])
+		}
+		rows = append(rows, row)
+		combinations = append(combinations, linearConstant(trans[0].X, trans[1].X, []*big.Int{trans[0].F_s}, []*big.Int{trans[1].F_s})[0])
+	}
+
+	keys, err := solve(rows, combinations)
+	if err != nil {
+		return Ring_Witness{}, err
+	}
+	witness.Keys = make([]*big.Int, N)
+	for i := range witness.Keys {
+		witness.Keys[i] = big.NewInt(0)
+	}
+	for j, i := range selected {
+		if !utils.Is_Equal_Point(utils.Commit(statement.Public_ck, keys[j]), statement.Pub_Vec_Key[i]) {
+			return Ring_Witness{}, errors.New("an extracted secret key does not match its public key")
+		}
+		witness.Keys[i] = keys[j]
+	}
+	return witness, nil
+}
+
+// Extract the value and the blinding factor from a range prover for a single commitment. Rewinding x three times
+// after the same (A, B, T1, T2) gives the bits from zeta and the blinding from the quadratic tau_x
+func RangeProofs(prover Range_Prover, statement range_proofs.Statement) (Range_Witness, error) {
+	d := statement.D
+	if len(statement.Pub_Vec_Coin) != 1 {
+		return Range_Witness{}, errors.New("the statement should contain one commitment")
+	}
+	A, B := prover.CommitAB()
+	y, z := challenge(), challenge()
+	prover.CommitT(y, z)
+
+	var trans []range_proofs.Transcript
+	for i := 0; i < 3; i++ {
+		t := prover.Respond(challenge())
+		if !utils.Is_Equal_Point(t.A, A) || !utils.Is_Equal_Point(t.B, B) {
+			return Range_Witness{}, errors.New("the prover changed its commitments")
+		}
+		if err := range_proofs.CheckTranscript(statement, t); err != nil {
+			return Range_Witness{}, err
+		}
+		trans = append(trans, t)
+	}
+	if !distinct(trans[0].X, trans[1].X, trans[2].X) {
+		return Range_Witness{}, errors.New("the rewound challenges collide")
+	}
+
+	// zeta(x) = b0 + z + s0 x
+	var witness Range_Witness
+	witness.B_0 = linearConstant(trans[0].X, trans[1].X, trans[0].Zeta, trans[1].Zeta)
+	witness.Value = big.NewInt(0)
+	for i := d - 1; i >= 0; i-- {
+		witness.B_0[i] = utils.Sub_In_P(witness.B_0[i], z)
+		if !isBit(witness.B_0[i]) {
+			return Range_Witness{}, errors.New("the extracted value has a non-binary digit")
+		}
+		witness.Value.Lsh(witness.Value, 1)
+		witness.Value.Add(witness.Value, witness.B_0[i])
+	}
+
+	// tau_x(x) = tau_2 x^2 + tau_1 x + z^2 gamma, interpolated at 0
+	c := big.NewInt(0)
+	for j := 0; j < 3; j++ {
+		l := big.NewInt(1)
+		for m := 0; m < 3; m++ {
+			if m != j {
+				l = utils.Mul_In_P(l, utils.Mul_In_P(trans[m].X, utils.Inverse_Zp(utils.Sub_In_P(trans[m].X, trans[j].X))))
+			}
+		}
+		c = utils.Add_In_P(c, utils.Mul_In_P(l, trans[j].Tau_x))
+	}
+	witness.Gamma = utils.Mul_In_P(c, utils.Inverse_Zp(utils.Mul_In_P(z, z)))
+
+	V := utils.Pedersen_Commit(statement.Gen_g, statement.Gen_h, witness.Value, witness.Gamma)
+	if !utils.Is_Equal_Point(V, statement.Pub_Vec_Coin[0]) {
+		return Range_Witness{}, errors.New("the extracted opening does not match the commitment")
+	}
+	return witness, nil
+}
+
+////////////////////////Private functions
+
+// Source of the rewinding challenges, replaced by tests
+var challenge = randomChallenge
+
+// Collect n verified responses of the prover to fresh challenges x
+func ringResponses(prover Ring_Prover, statement any_proofs.Statement, A utils.Point, B utils.Point, n int) ([]any_proofs.Transcript, error) {
+	var trans []any_proofs.Transcript
+	var xs []*big.Int
+	for i := 0; i < n; i++ {
+		t := prover.Respond(challenge())
+		if !utils.Is_Equal_Point(t.A, A) || !utils.Is_Equal_Point(t.B, B) {
+			return nil, errors.New("the prover changed its commitments")
+		}
+		if err := any_proofs.CheckTranscript(statement, t); err != nil {
+			return nil, err
+		}
+		trans = append(trans, t)
+		xs = append(xs, t.X)
+	}
+	if !distinct(xs...) {
+		return nil, errors.New("the rewound challenges collide")
+	}
+	return trans, nil
+}
+
+// Constant terms of the linear polynomials with values v1 at x1 and v2 at x2: (v1 x2 - v2 x1) / (x2 - x1)
+func linearConstant(x1 *big.Int, x2 *big.Int, v1 []*big.Int, v2 []*big.Int) []*big.Int {
+	inv := utils.Inverse_Zp(utils.Sub_In_P(x2, x1))
+	var res []*big.Int
+	for i := range v1 {
+		res = append(res, utils.Mul_In_P(utils.Sub_In_P(utils.Mul_In_P(v1[i], x2), utils.Mul_In_P(v2[i], x1)), inv))
+	}
+	return res
+}
+
+// Solve the square linear system rows \cdot sol = rhs in Zp by Gaussian elimination
+func solve(rows [][]*big.Int, rhs []*big.Int) ([]*big.Int, error) {
+	n := len(rhs)
+	var m [][]*big.Int
+	for i := range rows {
+		m = append(m, append(append([]*big.Int{}, rows[i]...), rhs[i]))
+	}
+	for col := 0; col < n; col++ {
+		pivot := -1
+		for r := col; r < n; r++ {
+			if m[r][col].Sign() != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			return nil, errors.New("the rewound challenges give a singular system")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		inv := utils.Inverse_Zp(m[col][col])
+		m[col] = utils.Cal_Sca_Vec(m[col], inv)
+		for r := 0; r < n; r++ {
+			if r != col && m[r][col].Sign() != 0 {
+				m[r] = utils.Cal_Sub_Vec(m[r], utils.Cal_Sca_Vec(m[col], m[r][col]))
+			}
+		}
+	}
+	var sol []*big.Int
+	for r := 0; r < n; r++ {
+		sol = append(sol, m[r][n])
+	}
+	return sol, nil
+}
+
+// Check that the challenges are pairwise distinct
+func distinct(xs ...*big.Int) bool {
+	for i := range xs {
+		for j := 0; j < i; j++ {
+			if xs[i].Cmp(xs[j]) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Check whether a scalar is 0 or 1
+func isBit(b *big.Int) bool {
+	return b.Sign() == 0 || b.Cmp(big.NewInt(1)) == 0
+}
+
+func equalVectors(a []*big.Int, b []*big.Int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Draw a random non-zero challenge in Zp
+func randomChallenge() *big.Int {
+	for {
+		c := utils.Add_In_P(utils.Generate_Random_Zp(256), big.NewInt(0))
+		if c.Sign() != 0 {
+			return c
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package extract
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/any_proofs"
+	"anyOutOfMany/range_proofs"
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Honest any-out-of-many prover with k secrets in a ring of N keys and its statement
+func ringProver(k int, N int) (*any_proofs.Prover, any_proofs.Statement) {
+	utils.Curve = secp256k1.S256()
+	ck, u, v := utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint()
+	G, H := utils.GenerateMultiPoint(N), utils.GenerateMultiPoint(N)
+	var prover any_proofs.Prover
+	prover.New(ck, utils.GeneratePoint(), u, v, G, H, k, N)
+	statement := any_proofs.Statement{Public_ck: ck, Gen_u: u, Gen_v: v, Gen_Vec_G: G, Gen_Vec_H: H, Pub_Vec_Key: prover.Pub_Vec_Key}
+	return &prover, statement
+}
+
+// Honest range prover of width d and its statement
+func rangeProver(d int) (*range_proofs.Prover, range_proofs.Statement) {
+	utils.Curve = secp256k1.S256()
+	g, h := utils.GeneratePoint(), utils.GeneratePoint()
+	G, H := utils.GenerateMultiPoint(d), utils.GenerateMultiPoint(d)
+	var prover range_proofs.Prover
+	prover.New(g, h, G, H, d)
+	statement := range_proofs.Statement{Gen_g: g, Gen_h: h, Gen_Vec_G: G, Gen_Vec_H: H, Pub_Vec_Coin: []utils.Point{prover.Pub_Coin}, D: d}
+	return &prover, statement
+}
+
+func TestAnyProofs(t *testing.T) {
+	prover, statement := ringProver(3, 8)
+	witness, err := AnyProofs(prover, statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selected := 0
+	for i, b := range witness.B_0 {
+		if b.Sign() == 0 {
+			continue
+		}
+		selected++
+		if !utils.Is_Equal_Point(utils.Commit(statement.Public_ck, witness.Keys[i]), statement.Pub_Vec_Key[i]) {
+			t.Errorf("the key extracted at position %d does not open its public key", i)
+		}
+	}
+	if selected != 3 {
+		t.Fatalf("extracted %d keys, want 3", selected)
+	}
+}
+
+func TestRangeProofs(t *testing.T) {
+	prover, statement := rangeProver(16)
+	witness, err := RangeProofs(prover, statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if witness.Value.Cmp(new(big.Int).Lsh(big.NewInt(1), 16)) >= 0 {
+		t.Fatalf("extracted value %v is out of range", witness.Value)
+	}
+	V := utils.Pedersen_Commit(statement.Gen_g, statement.Gen_h, witness.Value, witness.Gamma)
+	if !utils.Is_Equal_Point(V, prover.Pub_Coin) {
+		t.Fatal("the extracted opening does not match the commitment")
+	}
+}
+
+// Rewinding with repeated challenges gives no independent equations, the extractors report it instead of
+// dividing by zero
+func TestCollidingChallenges(t *testing.T) {
+	defer func() { challenge = randomChallenge }()
+	challenge = func() *big.Int { return big.NewInt(7) }
+
+	prover, statement := ringProver(1, 4)
+	if _, err := AnyProofs(prover, statement); err == nil {
+		t.Fatal("any-out-of-many extraction succeeded with colliding challenges")
+	}
+	range_prover, range_statement := rangeProver(8)
+	if _, err := RangeProofs(range_prover, range_statement); err == nil {
+		t.Fatal("range extraction succeeded with colliding challenges")
+	}
+
+	// Fresh challenges x but the same y, z in every fork cannot separate two keys
+	calls := int64(0)
+	challenge = func() *big.Int {
+		calls++
+		if calls%4 == 1 || calls%4 == 2 {
+			return big.NewInt(calls % 4)
+		}
+		return big.NewInt(100 + calls)
+	}
+	prover, statement = ringProver(2, 4)
+	if _, err := AnyProofs(prover, statement); err == nil {
+		t.Fatal("any-out-of-many extraction succeeded with colliding challenges y")
+	}
+}
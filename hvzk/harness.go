@@ -0,0 +1,225 @@
+package hvzk
+
+import (
+	"fmt"
+	"math/big"
+
+	"anyOutOfMany/any_proofs"
+	"anyOutOfMany/omniring"
+	"anyOutOfMany/range_proofs"
+	"anyOutOfMany/utils"
+)
+
+// Statistical harness comparing real transcripts with the output of the honest-verifier zero-knowledge simulators.
+// Every scalar and every point (by its X coordinate) of a transcript is a field, the real and simulated samples of each
+// field are bucketed by their low bits and compared with a two-sample chi-square test. The simulated transcripts are
+// generated for the statements and challenges of the real ones and must pass the verification equations
+// The curve utils.Curve must be set by the caller
+//
+// The test is a sanity check, not a proof of zero knowledge. It looks at the low 4 bits of one field at a time, so
+// it misses biases in the high bits, correlations between fields or with the statement, and any distinguisher that
+// uses the group structure. With n samples per side it only resolves biases of roughly 1/sqrt(n) per bucket, and
+// since every field is tested at p = 1e-4 a transcript with many fields fails by chance about once per 1e4/fields runs
+
+const buckets = 16
+
+// Chi-square quantile with buckets-1 = 15 degrees of freedom at p = 1e-4
+const Critical = 44.26
+
+// Result of a comparison
+type Report struct {
+	Samples    int
+	Fields     []string
+	Chi_Square []float64 // statistic of every field
+	Invalid    int       // simulated transcripts rejected by the verification equations
+}
+
+// Check that every simulated transcript is valid and no field distinguishes real from simulated transcripts
+func (report *Report) Passed() bool {
+	if report.Invalid > 0 {
+		return false
+	}
+	for _, chi := range report.Chi_Square {
+		if chi > Critical {
+			return false
+		}
+	}
+	return true
+}
+
+func (report *Report) String() string {
+	worst, field := 0.0, ""
+	for i, chi := range report.Chi_Square {
+		if chi >= worst {
+			worst, field = chi, report.Fields[i]
+		}
+	}
+	return fmt.Sprintf("%d samples, %d fields, largest chi-square %.2f (%s, critical %.2f), %d invalid simulated transcripts",
+		report.Samples, len(report.Fields), worst, field, Critical, report.Invalid)
+}
+
+// Compare the samples of named fields, real[i] and simulated[i] being the fields of the i-th transcripts
+func Compare(fields []string, real [][]*big.Int, simulated [][]*big.Int) Report {
+	report := Report{Samples: len(real), Fields: fields}
+	for f := range fields {
+		var count_r, count_s [buckets]int
+		for i := range real {
+			count_r[bucket(real[i][f])]++
+		}
+		for i := range simulated {
+			count_s[bucket(simulated[i][f])]++
+		}
+		report.Chi_Square = append(report.Chi_Square, chiSquare(count_r, count_s, len(real), len(simulated)))
+	}
+	return report
+}
+
+// Compare real and simulated any-out-of-many transcripts with k secrets in rings of size N
+func AnyProofs(k int, N int, samples int) (Report, error) {
+	if k <= 0 || k > N || samples <= 0 {
+		return Report{}, fmt.Errorf("invalid parameters k = %d, N = %d, samples = %d", k, N, samples)
+	}
+	ck, u, v := utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint()
+	G, H := utils.GenerateMultiPoint(N), utils.GenerateMultiPoint(N)
+	var real, simulated [][]*big.Int
+	invalid := 0
+	for i := 0; i < samples; i++ {
+		var prover any_proofs.Prover
+		prover.New(ck, utils.GeneratePoint(), u, v, G, H, k, N)
+		ring, trans := prover.GenerateRsp()
+		statement := any_proofs.Statement{Public_ck: ck, Gen_u: u, Gen_v: v, Gen_Vec_G: G, Gen_Vec_H: H, Pub_Vec_Key: ring}
+		sim := any_proofs.Simulate(statement, any_proofs.Challenges{Y: trans.Y, Z: trans.Z, X: trans.X})
+		if any_proofs.CheckTranscript(statement, sim) != nil {
+			invalid++
+		}
+		real = append(real, anyProofsFields(trans))
+		simulated = append(simulated, anyProofsFields(sim))
+	}
+	report := Compare(anyProofsNames(N), real, simulated)
+	report.Invalid = invalid
+	return report, nil
+}
+
+// Compare real and simulated range proof transcripts of width d
+func RangeProofs(d int, samples int) (Report, error) {
+	if d <= 0 || d > 64 || samples <= 0 {
+		return Report{}, fmt.Errorf("invalid parameters d = %d, samples = %d", d, samples)
+	}
+	g, h := utils.GeneratePoint(), utils.GeneratePoint()
+	G, H := utils.GenerateMultiPoint(d), utils.GenerateMultiPoint(d)
+	var real, simulated [][]*big.Int
+	invalid := 0
+	for i := 0; i < samples; i++ {
+		var prover range_proofs.Prover
+		prover.New(g, h, G, H, d)
+		V, trans := prover.GenerateRsp()
+		statement := range_proofs.Statement{Gen_g: g, Gen_h: h, Gen_Vec_G: G, Gen_Vec_H: H, Pub_Vec_Coin: []utils.Point{V}, D: d}
+		sim := range_proofs.Simulate(statement, range_proofs.Challenges{Y: trans.Y, Z: trans.Z, X: trans.X})
+		if range_proofs.CheckTranscript(statement, sim) != nil {
+			invalid++
+		}
+		real = append(real, rangeProofsFields(trans))
+		simulated = append(simulated, rangeProofsFields(sim))
+	}
+	report := Compare(rangeProofsNames(d), real, simulated)
+	report.Invalid = invalid
+	return report, nil
+}
+
+// Compare real and simulated omniring transcripts with k secrets, N source accounts and value width d
+func Omniring(k int, N int, d int, samples int) (Report, error) {
+	if k <= 0 || N%k != 0 || d <= 0 || samples <= 0 {
+		return Report{}, fmt.Errorf("invalid parameters k = %d, N = %d, d = %d, samples = %d", k, N, d, samples)
+	}
+	n := N / k
+	u, v := utils.Generate_Random_Zp(d), utils.Generate_Random_Zp(d)
+	F, G, H := utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint()
+	P_Vector := utils.GenerateMultiPoint(2 + N)
+	G_Vector := utils.GenerateMultiPoint(3*k + N)
+	H_Vector := utils.GenerateMultiPoint(2 + n + 3*k + N)
+	var real, simulated [][]*big.Int
+	invalid := 0
+	for i := 0; i < samples; i++ {
+		var prover omniring.Prover
+		var verifier omniring.Verifier
+		prover.New(u, v, F, G, H, P_Vector, G_Vector, H_Vector, k, N, d)
+		verifier.New(u, v, F, G, H, P_Vector, G_Vector, H_Vector, k, N, d)
+		keys, inputs, outputs, trans, _, _ := prover.GenerateRsp()
+		verifier.Pub_Vec_Key = keys
+		verifier.Inp_Vec_Coin = inputs
+		verifier.Out_Vec_Coin = outputs
+		sim := verifier.Simulate(omniring.Challenges{W: trans.W, Y: trans.Y, Z: trans.Z, X: trans.X})
+		if !verifier.CheckTranscript(sim) {
+			invalid++
+		}
+		real = append(real, omniringFields(trans))
+		simulated = append(simulated, omniringFields(sim))
+	}
+	report := Compare(omniringNames(2+n+N+3*k), real, simulated)
+	report.Invalid = invalid
+	return report, nil
+}
+
+////////////////////////Private functions
+
+// Bucket of a value by its low bits
+func bucket(value *big.Int) int {
+	return int(new(big.Int).And(value, big.NewInt(buckets-1)).Int64())
+}
+
+// Two-sample chi-square statistic of bucket counts
+func chiSquare(count_r [buckets]int, count_s [buckets]int, n_r int, n_s int) float64 {
+	k_r := float64(n_s) / float64(n_r)
+	k_s := float64(n_r) / float64(n_s)
+	chi := 0.0
+	for i := 0; i < buckets; i++ {
+		if count_r[i]+count_s[i] == 0 {
+			continue
+		}
+		diff := k_r*float64(count_r[i]) - k_s*float64(count_s[i])
+		chi += diff * diff / float64(count_r[i]+count_s[i])
+	}
+	// Normalize for unequal sample sizes, reducing to \sum (R_i - S_i)^2 / (R_i + S_i) when n_r = n_s
+	return chi / (k_r * k_s)
+}
+
+// Names of the commitments, scalars and openings of a transcript
+func names(points []string, scalars []string, vectors []string, n int) []string {
+	res := append(append([]string{}, points...), scalars...)
+	for _, vec := range vectors {
+		for i := 0; i < n; i++ {
+			res = append(res, fmt.Sprintf("%s[%d]", vec, i))
+		}
+	}
+	return res
+}
+
+func anyProofsNames(N int) []string {
+	return names([]string{"A", "B", "T1", "T2", "E"}, []string{"Tau_x", "Mu", "Ip", "F_s"}, []string{"Zeta", "Eta"}, N)
+}
+
+func anyProofsFields(trans any_proofs.Transcript) []*big.Int {
+	res := []*big.Int{trans.A.X, trans.B.X, trans.T1.X, trans.T2.X, trans.E.X, trans.Tau_x, trans.Mu, trans.Ip, trans.F_s}
+	res = append(res, trans.Zeta...)
+	return append(res, trans.Eta...)
+}
+
+func rangeProofsNames(d int) []string {
+	return names([]string{"A", "B", "T1", "T2"}, []string{"Tau_x", "Mu", "Ip"}, []string{"Zeta", "Eta"}, d)
+}
+
+func rangeProofsFields(trans range_proofs.Transcript) []*big.Int {
+	res := []*big.Int{trans.A.X, trans.B.X, trans.T1.X, trans.T2.X, trans.Tau_x, trans.Mu, trans.Ip}
+	res = append(res, trans.Zeta...)
+	return append(res, trans.Eta...)
+}
+
+func omniringNames(size int) []string {
+	return names([]string{"A", "B", "T1", "T2"}, []string{"Tau_x", "Mu", "Ip"}, []string{"Zeta", "Eta"}, size)
+}
+
+func omniringFields(trans omniring.Transcript) []*big.Int {
+	res := []*big.Int{trans.A.X, trans.B.X, trans.T1.X, trans.T2.X, trans.Tau_x, trans.Mu, trans.Ip}
+	res = append(res, trans.Zeta...)
+	return append(res, trans.Eta...)
+}
@@ -0,0 +1,53 @@
+package hvzk
+
+import (
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+const samples = 200
+
+func TestAnyProofs(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	report, err := AnyProofs(2, 4, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Passed() {
+		t.Fatal(report.String())
+	}
+}
+
+func TestRangeProofs(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	report, err := RangeProofs(8, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Passed() {
+		t.Fatal(report.String())
+	}
+}
+
+func TestOmniring(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	report, err := Omniring(1, 4, 16, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Passed() {
+		t.Fatal(report.String())
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	if _, err := AnyProofs(3, 2, samples); err == nil {
+		t.Fatal("more secrets than ring members accepted")
+	}
+	if _, err := RangeProofs(8, 0); err == nil {
+		t.Fatal("zero samples accepted")
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"math"
 	"math/big"
 	"time"
 
@@ -29,8 +28,7 @@ func OurRingCT() {
 
 	var ap_prover any_proofs.Prover
 	var ap_verifier any_proofs.Verifier
-	var rp_prover [m]range_proofs.Prover
-	var rp_verifier [m]range_proofs.Verifier
+	var rp_params range_proofs.Params
 
 	p_start := time.Now()
 	fmt.Println("Initialize Any-out-of-Many Proofs")
@@ -38,58 +36,26 @@ func OurRingCT() {
 	fmt.Println("Generate Any-out-of-Many Proofs")
 	zeta_p, eta_p, Vec_g_p, Vec_h_p := anyProofsProve(&ap_prover, &ap_verifier)
 	g_v := ap_prover.Gen_v
-	fmt.Println("Initialize and Generate Range Proofs")
-	for i := 0; i < m; i++ {
-		var temp_rp_prover range_proofs.Prover
-		var temp_rp_verifier range_proofs.Verifier
-		rangeProofsSetup(k, N, d, &temp_rp_prover, &temp_rp_verifier)
-		rp_prover[i] = temp_rp_prover
-		rp_verifier[i] = temp_rp_verifier
-
-		temp_zeta, temp_eta, temp_Vec_h := rangeProofsProve(&rp_prover[i], &rp_verifier[i])
-		zeta_p = append(zeta_p, temp_zeta...)
-		eta_p = append(eta_p, temp_eta...)
-		Vec_g_p = append(Vec_g_p, rp_prover[i].Gen_Vec_G...)
-		Vec_h_p = append(Vec_h_p, temp_Vec_h...)
-		g_v = utils.Cal_Point_Add(g_v, rp_prover[i].Gen_g)
-	}
-
-	// Padding the vectors to 2^n length
-	deg := math.Ceil(math.Log2(float64(len(zeta_p))))
-	pad := math.Pow(2, deg) - float64(len(zeta_p))
-	zero_vec := Generate_cons_vec(int(pad), big.NewInt(0))
-	var one_vec []utils.Point
-	for i := 0; i < len(zero_vec); i++ {
-		one_vec = append(one_vec, utils.Commit(ap_prover.Gen_u, big.NewInt(0)))
-	}
-	zeta_p = append(zeta_p, zero_vec...)
-	eta_p = append(eta_p, zero_vec...)
-	Vec_g_p = append(Vec_g_p, one_vec...)
-	Vec_h_p = append(Vec_h_p, one_vec...)
 
 	fmt.Println("Execute Optimization")
-	L, R, C_zeta, C_eta := opt_prove(zeta_p, eta_p, Vec_g_p, Vec_h_p, g_v)
-	p_elapsed := time.Since(p_start)
+	L, R, C_zeta, C_eta := utils.Opt_Prove(ipaTranscript(utils.Pedersen_Commit_Vector(Vec_g_p, Vec_h_p, zeta_p, eta_p)), zeta_p, eta_p, Vec_g_p, Vec_h_p, g_v)
 
-	RHS, Vec_g_v := anyProofsVerify(&ap_verifier)
-	Vec_h_v := ap_verifier.Gen_Vec_H
-
-	for i := 0; i < m; i++ {
-		temp_RHS := rangeProofsVerify(&rp_verifier[i])
-		RHS = utils.Cal_Point_Add(RHS, temp_RHS)
-		Vec_g_v = append(Vec_g_v, rp_verifier[i].Gen_Vec_G...)
-		Vec_h_v = append(Vec_h_v, rp_verifier[i].Gen_Vec_H...)
-	}
+	fmt.Println("Initialize and Generate Aggregated Range Proofs")
+	rangeProofsSetup(d, m, &rp_params)
+	rp_statement, rp_proof := rangeProofsProve(&rp_params, d, m)
+	p_elapsed := time.Since(p_start)
 
-	// Padding the vectors to 2^n length
-	Vec_g_v = append(Vec_g_v, one_vec...)
-	Vec_h_v = append(Vec_h_v, one_vec...)
+	RHS, Vec_g_v, Vec_h_v := anyProofsVerify(&ap_verifier)
+	ip := ap_verifier.Trans.Ip
 
 	v_start := time.Now()
-	fmt.Println("Verify Aggregated Proofs")
-	opt_verify(L, R, RHS, Vec_g_v, Vec_h_v, g_v, C_zeta, C_eta)
+	fmt.Println("Verify Proofs")
+	P := utils.Cal_Point_Add(RHS, utils.Commit(g_v, utils.Neg_Zp(ip)))
+	res := utils.Opt_Verify(ipaTranscript(P), L, R, P, ip, Vec_g_v, Vec_h_v, g_v, C_zeta, C_eta)
+	res = res && rangeProofsVerify(rp_statement, rp_proof)
 
 	v_elapsed := time.Since(v_start)
+	fmt.Println("Verification Result:", res)
 	fmt.Println("Prover Running Time:", p_elapsed)
 	fmt.Println("Verify Running Time:", v_elapsed)
 
@@ -99,8 +65,7 @@ func Omniring() {
 
 	var or_prover omniring.Prover
 	var or_verifier omniring.Verifier
-	var rp_prover [m]range_proofs.Prover
-	var rp_verifier [m]range_proofs.Verifier
+	var rp_params range_proofs.Params
 
 	p_start := time.Now()
 	fmt.Println("Initialize Ring Signature Proofs")
@@ -109,57 +74,25 @@ func Omniring() {
 	zeta_p, eta_p, Vec_g_p, Vec_h_p := omniringProve(&or_prover, &or_verifier)
 	g_v := or_prover.Gen_G
 
-	fmt.Println("Initialize and Generate Range Proofs")
-	for i := 0; i < m; i++ {
-		var temp_rp_prover range_proofs.Prover
-		var temp_rp_verifier range_proofs.Verifier
-		rangeProofsSetup(k, N, d, &temp_rp_prover, &temp_rp_verifier)
-		rp_prover[i] = temp_rp_prover
-		rp_verifier[i] = temp_rp_verifier
-
-		temp_zeta, temp_eta, temp_Vec_h := rangeProofsProve(&rp_prover[i], &rp_verifier[i])
-		zeta_p = append(zeta_p, temp_zeta...)
-		eta_p = append(eta_p, temp_eta...)
-		Vec_g_p = append(Vec_g_p, rp_prover[i].Gen_Vec_G...)
-		Vec_h_p = append(Vec_h_p, temp_Vec_h...)
-		g_v = utils.Cal_Point_Add(g_v, rp_prover[i].Gen_g)
-	}
-
-	// Padding the vectors to 2^n length
-	deg := math.Ceil(math.Log2(float64(len(zeta_p))))
-	pad := math.Pow(2, deg) - float64(len(zeta_p))
-	zero_vec := Generate_cons_vec(int(pad), big.NewInt(0))
-	var one_vec []utils.Point
-	for i := 0; i < len(zero_vec); i++ {
-		one_vec = append(one_vec, utils.Commit(or_prover.Gen_F, big.NewInt(0)))
-	}
-	zeta_p = append(zeta_p, zero_vec...)
-	eta_p = append(eta_p, zero_vec...)
-	Vec_g_p = append(Vec_g_p, one_vec...)
-	Vec_h_p = append(Vec_h_p, one_vec...)
-
 	fmt.Println("Execute Optimization")
-	L, R, C_zeta, C_eta := opt_prove(zeta_p, eta_p, Vec_g_p, Vec_h_p, g_v)
+	L, R, C_zeta, C_eta := utils.Opt_Prove(ipaTranscript(utils.Pedersen_Commit_Vector(Vec_g_p, Vec_h_p, zeta_p, eta_p)), zeta_p, eta_p, Vec_g_p, Vec_h_p, g_v)
+
+	fmt.Println("Initialize and Generate Aggregated Range Proofs")
+	rangeProofsSetup(d, m, &rp_params)
+	rp_statement, rp_proof := rangeProofsProve(&rp_params, d, m)
 	p_elapsed := time.Since(p_start)
 
 	RHS, Vec_g_v, Vec_h_v := omniringVerify(&or_verifier)
-
-	for i := 0; i < m; i++ {
-		temp_RHS := rangeProofsVerify(&rp_verifier[i])
-		RHS = utils.Cal_Point_Add(RHS, temp_RHS)
-		Vec_g_v = append(Vec_g_v, rp_verifier[i].Gen_Vec_G...)
-		Vec_h_v = append(Vec_h_v, rp_verifier[i].Gen_Vec_H...)
-	}
-
-	// Padding the vectors to 2^n length
-	Vec_g_v = append(Vec_g_v, one_vec...)
-	Vec_h_v = append(Vec_h_v, one_vec...)
+	ip := or_verifier.Trans.Ip
 
 	v_start := time.Now()
-	fmt.Println("Verify Aggregated Proofs")
-	opt_verify(L, R, RHS, Vec_g_v, Vec_h_v, g_v, C_zeta, C_eta)
+	fmt.Println("Verify Proofs")
+	P := utils.Cal_Point_Add(RHS, utils.Commit(g_v, utils.Neg_Zp(ip)))
+	res := utils.Opt_Verify(ipaTranscript(P), L, R, P, ip, Vec_g_v, Vec_h_v, g_v, C_zeta, C_eta)
+	res = res && rangeProofsVerify(rp_statement, rp_proof)
 
 	v_elapsed := time.Since(v_start)
+	fmt.Println("Verification Result:", res)
 	fmt.Println("Prover Running Time:", p_elapsed)
 	fmt.Println("Verify Running Time:", v_elapsed)
 
@@ -197,54 +130,50 @@ func anyProofsProve(prover *any_proofs.Prover, verifier *any_proofs.Verifier) ([
 	Vec_G_P := utils.Cal_Point_Add_Vec(prover.Gen_Vec_G, Pub_Key_yN)
 
 	return trans.Zeta, trans.Eta, Vec_G_P, Inv_Vec_H
-	// ap_verifier.L, ap_verifier.R, ap_verifier.C_zeta, ap_verifier.C_eta = opt_prove(trans.Zeta, trans.Eta, Vec_G_P, Inv_Vec_H, ap_prover.Gen_v)
+	// ap_verifier.L, ap_verifier.R, ap_verifier.C_zeta, ap_verifier.C_eta = utils.Opt_Prove(trans.Zeta, trans.Eta, Vec_G_P, Inv_Vec_H, ap_prover.Gen_v)
 	// fmt.Println("ZKProofs Generated...")
 }
 
-func anyProofsVerify(verifier *any_proofs.Verifier) (utils.Point, []utils.Point) {
+func anyProofsVerify(verifier *any_proofs.Verifier) (utils.Point, []utils.Point, []utils.Point) {
 	RHS := verifier.ParseZKP()
 
 	Pub_Key_yN := utils.Generate_Point_Vector_with_y(verifier.Pub_Vec_Key, verifier.YN)
 	Vec_G_P := utils.Cal_Point_Add_Vec(verifier.Gen_Vec_G, Pub_Key_yN)
+	Inv_yN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(verifier.Trans.Y), verifier.N)
+	Inv_Vec_H := utils.Generate_Point_Vector_with_y(verifier.Gen_Vec_H, Inv_yN)
 
-	return RHS, Vec_G_P
-	// opt_verify(ap_verifier.L, ap_verifier.R, RHS, Vec_G_P, ap_verifier.Gen_Vec_H, ap_verifier.Gen_v, ap_verifier.C_zeta, ap_verifier.C_eta, ap_verifier.Trans.Y)
+	return RHS, Vec_G_P, Inv_Vec_H
+	// utils.Opt_Verify(ap_verifier.L, ap_verifier.R, RHS, Vec_G_P, ap_verifier.Gen_Vec_H, ap_verifier.Gen_v, ap_verifier.C_zeta, ap_verifier.C_eta, ap_verifier.Trans.Y)
 }
 
-func rangeProofsSetup(k int, N int, d int, prover *range_proofs.Prover, verifier *range_proofs.Verifier) {
+func rangeProofsSetup(d int, m int, params *range_proofs.Params) {
 
 	utils.Curve = secp256k1.S256() //Choose an elliptic curve
 
-	g := utils.GeneratePoint()
-	h := utils.GeneratePoint()
-
-	g_Vector := utils.GenerateMultiPoint(d)
-	h_Vector := utils.GenerateMultiPoint(d)
-
-	//construct an object prover
-	prover.New(g, h, g_Vector, h_Vector, d)
-	verifier.New(g, h, g_Vector, h_Vector, d)
+	//construct the public parameters shared by the m values, serving any width up to d
+	params.Setup(d, m)
 }
 
-func rangeProofsProve(prover *range_proofs.Prover, verifier *range_proofs.Verifier) ([]*big.Int, []*big.Int, []utils.Point) {
+func rangeProofsProve(params *range_proofs.Params, d int, m int) (range_proofs.Statement, range_proofs.Proof) {
 
-	//verifier get response
-	Pub_Coin, trans := prover.GenerateRsp()
-	verifier.Pub_Coin = Pub_Coin
-	verifier.Trans = trans
+	// Sample m values in [0, 2^d) with their blinding factors
+	var values []uint64
+	var blindings []*big.Int
+	for i := 0; i < m; i++ {
+		values = append(values, utils.Generate_Random_Zp(d).Uint64())
+		blindings = append(blindings, utils.Generate_Random_Zp(256))
+	}
 
-	// Compress the openings
-	Inv_yN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(trans.Y), prover.D)
-	Inv_Vec_H := utils.Generate_Point_Vector_with_y(prover.Gen_Vec_H, Inv_yN)
-	// rp_verifier.L, rp_verifier.R, rp_verifier.C_zeta, rp_verifier.C_eta = opt_prove(trans.Zeta, trans.Eta, rp_prover.Gen_Vec_G, Inv_Vec_H, rp_prover.Gen_g)
-	// fmt.Println("ZKProofs Generated...")
-	return trans.Zeta, trans.Eta, Inv_Vec_H
+	// Generate one aggregated proof for all values
+	statement, proof, err := params.ProveMultiple(values, blindings, d)
+	if err != nil {
+		fmt.Println("Failed to generate range proofs:", err)
+	}
+	return statement, proof
 }
 
-func rangeProofsVerify(verifier *range_proofs.Verifier) utils.Point {
-	RHS := verifier.ParseZKP()
-	// opt_verify(rp_verifier.L, rp_verifier.R, RHS, rp_verifier.Gen_Vec_G, rp_verifier.Gen_Vec_H, rp_verifier.Gen_g, rp_verifier.C_zeta, rp_verifier.C_eta, rp_verifier.Trans.Y)
-	return RHS
+func rangeProofsVerify(statement range_proofs.Statement, proof range_proofs.Proof) bool {
+	return range_proofs.Verify(statement, proof) == nil
 }
 
 func omniringSetup(k int, N int, d int, prover *omniring.Prover, verifier *omniring.Verifier) {
@@ -275,12 +204,9 @@ func omniringProve(prover *omniring.Prover, verifier *omniring.Verifier) ([]*big
 	verifier.Trans = trans
 	verifier.Inp_Vec_Coin = pub_Inp_Coin
 	verifier.Out_Vec_Coin = pub_Out_Coin
-	fmt.Println(len(pub_Out_Coin))
-	// Compress the openings
-	verifier.L, verifier.R, verifier.C_zeta, verifier.C_zeta = opt_prove(trans.Zeta, trans.Eta, Gen_Vec_G, Gen_Vec_H, prover.Gen_G)
 
 	return trans.Zeta, trans.Eta, Gen_Vec_G, Gen_Vec_H
-	// ap_verifier.L, ap_verifier.R, ap_verifier.C_zeta, ap_verifier.C_eta = opt_prove(trans.Zeta, trans.Eta, Vec_G_P, Inv_Vec_H, ap_prover.Gen_v)
+	// ap_verifier.L, ap_verifier.R, ap_verifier.C_zeta, ap_verifier.C_eta = utils.Opt_Prove(trans.Zeta, trans.Eta, Vec_G_P, Inv_Vec_H, ap_prover.Gen_v)
 	// fmt.Println("ZKProofs Generated...")
 }
 
@@ -288,14 +214,32 @@ func omniringVerify(verifier *omniring.Verifier) (utils.Point, []utils.Point, []
 	RHS, Gen_Vec_G, Gen_Vec_H := verifier.ParseZKP()
 
 	return RHS, Gen_Vec_G, Gen_Vec_H
-	// opt_verify(ap_verifier.L, ap_verifier.R, RHS, Vec_G_P, ap_verifier.Gen_Vec_H, ap_verifier.Gen_v, ap_verifier.C_zeta, ap_verifier.C_eta, ap_verifier.Trans.Y)
+	// utils.Opt_Verify(ap_verifier.L, ap_verifier.R, RHS, Vec_G_P, ap_verifier.Gen_Vec_H, ap_verifier.Gen_v, ap_verifier.C_zeta, ap_verifier.C_eta, ap_verifier.Trans.Y)
+}
+
+//Start the transcript of the inner-product argument for the commitment P of the openings
+func ipaTranscript(P utils.Point) *utils.Fiat_Shamir {
+	var fs utils.Fiat_Shamir
+	fs.New("main/inner_product")
+	fs.Append_Point("P", P)
+	return &fs
+}
+
+//Generete scalar vector z*1^n = (z,...,z)
+func Generate_Scalar_Vector(z *big.Int, n int) []*big.Int {
+	var Scalar_Vector []*big.Int
+	for i := n; i > 0; i-- {
+		Scalar_Vector = append(Scalar_Vector, z)
+	}
+	return Scalar_Vector
 }
 
-//Generete constant vector
-func Generate_cons_vec(n int, c *big.Int) []*big.Int {
-	var zero []*big.Int
-	for i := 0; i < n; i++ {
-		zero = append(zero, c)
+//Generete exponential scalar vector y^n = (y^1,...,y^n)
+func Generate_Exp_Scalar_Vector(y *big.Int, n int) []*big.Int {
+	var Scalar_Vector []*big.Int
+	Scalar_Vector = append(Scalar_Vector, big.NewInt(1))
+	for i := 1; i < n; i++ {
+		Scalar_Vector = append(Scalar_Vector, utils.Mul_In_P(Scalar_Vector[i-1], y))
 	}
-	return zero
+	return Scalar_Vector
 }
@@ -0,0 +1,58 @@
+package membership
+
+import (
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+//Generete the one-hot vector b_0 selecting index l out of n
+func Generate_b_0(l int, n int) []*big.Int {
+	var b_0 []*big.Int
+	for i := 0; i < n; i++ {
+		if i == l {
+			b_0 = append(b_0, big.NewInt(1))
+		} else {
+			b_0 = append(b_0, big.NewInt(0))
+		}
+	}
+	return b_0
+}
+
+//Generete vector b_1 according to b_0
+func Generate_b_1(b_0 []*big.Int) (b_1 []*big.Int) {
+	for i := 0; i < len(b_0); i++ {
+		b_1 = append(b_1, utils.Sub_In_P(big.NewInt(1), b_0[i]))
+	}
+	return b_1
+}
+
+//Generete exponential scalar vector y^n = (y^1,...,y^n)
+func Generate_Exp_Scalar_Vector(y *big.Int, n int) []*big.Int {
+	var Scalar_Vector []*big.Int
+	Scalar_Vector = append(Scalar_Vector, big.NewInt(1))
+	for i := 1; i < n; i++ {
+		Scalar_Vector = append(Scalar_Vector, utils.Mul_In_P(Scalar_Vector[i-1], y))
+	}
+	return Scalar_Vector
+}
+
+//Generete scalar vector z*1^n = (z,...,z)
+func Generate_Scalar_Vector(z *big.Int, n int) []*big.Int {
+	var Scalar_Vector []*big.Int
+	for i := n; i > 0; i-- {
+		Scalar_Vector = append(Scalar_Vector, z)
+	}
+	return Scalar_Vector
+}
+
+//Generete the set vector z^2 \cdot 1^n + z^3 \cdot s binding <b_0, 1> = 1 and <b_0, s> = v
+func Generate_Set_Vector(z *big.Int, set []*big.Int) []*big.Int {
+	var Scalar_Vector []*big.Int
+	z2 := utils.Mul_In_P(z, z)
+	z3 := utils.Mul_In_P(z2, z)
+	for i := range set {
+		Scalar_Vector = append(Scalar_Vector, utils.Add_In_P(z2, utils.Mul_In_P(z3, set[i])))
+	}
+	return Scalar_Vector
+}
@@ -0,0 +1,136 @@
+package membership
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Public statement of a membership proof: generators, the commitment V and the public set
+type Statement struct {
+	Gen_g, Gen_h         utils.Point   // generators g,h
+	Gen_Vec_G, Gen_Vec_H []utils.Point // generator vector g h, only the first len(Set) are used
+	Pub_Coin             utils.Point   // commitment V = g^v h^gamma
+	Set                  []*big.Int    // public set {s_1,...,s_n}
+}
+
+// Non-interactive membership proof, the challenges are recomputed by the verifier
+type Proof struct {
+	A, B, T1, T2  utils.Point
+	Tau_x, Mu, Ip *big.Int
+	L, R          []utils.Point // inner-product argument rounds
+	C_zeta, C_eta *big.Int      // compressed openings zeta, eta
+}
+
+// Error returned by BatchVerify, listing the indices of the invalid proofs
+type BatchError = utils.Batch_Error
+
+// Verify a single membership proof
+func Verify(statement Statement, proof Proof) error {
+	if err := BatchVerify([]Statement{statement}, []Proof{proof}); err != nil {
+		return errors.New("invalid membership proof")
+	}
+	return nil
+}
+
+// Verify many membership proofs with one randomized multi-exponentiation
+func BatchVerify(statements []Statement, proofs []Proof) error {
+	if len(statements) != len(proofs) {
+		return errors.New("the number of statements and proofs should be equal")
+	}
+	return utils.Batch_Verify("membership proofs", len(proofs), func(me *utils.Multi_Exp, i int) error {
+		return addVerifyTerms(me, statements[i], proofs[i])
+	})
+}
+
+////////////////////////Private functions
+
+// Check that a statement and proof are well formed
+func checkProofFormat(statement Statement, proof Proof) error {
+	n := len(statement.Set)
+	if n == 0 || len(statement.Gen_Vec_G) < n || len(statement.Gen_Vec_H) < n {
+		return errors.New("the generator vectors should be at least as long as the non-empty set")
+	}
+	points := []utils.Point{statement.Gen_g, statement.Gen_h, statement.Pub_Coin, proof.A, proof.B, proof.T1, proof.T2}
+	points = append(points, statement.Gen_Vec_G[:n]...)
+	points = append(points, statement.Gen_Vec_H[:n]...)
+	points = append(points, proof.L...)
+	points = append(points, proof.R...)
+	for _, point := range points {
+		if !utils.Is_Valid_Point(point) {
+			return errors.New("the proof contains an invalid point")
+		}
+	}
+	scalars := []*big.Int{proof.Tau_x, proof.Mu, proof.Ip, proof.C_zeta, proof.C_eta}
+	for _, scalar := range append(scalars, statement.Set...) {
+		if !utils.Is_Valid_Scalar(scalar) {
+			return errors.New("the proof contains an invalid scalar")
+		}
+	}
+	return nil
+}
+
+// Add the two verification equations of one proof, each weighted by a fresh random value:
+// (1) g^{ip} h^{tau_x} = V^{z^3} g^{delta} T1^x T2^{x^2}
+// (2) g^{a s} (h^{y^{-n}})^{b s'} g^{x_ip (ab - ip)} L^{-x} R^{-x^{-1}} = A B^x g^z (h^{y^{-n}})^{z y^n + z^2 1^n + z^3 s} h^{-mu}
+func addVerifyTerms(me *utils.Multi_Exp, statement Statement, proof Proof) error {
+	if err := checkProofFormat(statement, proof); err != nil {
+		return err
+	}
+	n := len(statement.Set)
+
+	// Recompute the challenges
+	fs := statement.newTranscript()
+	fs.Append_Point("A", proof.A)
+	fs.Append_Point("B", proof.B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+	fs.Append_Point("T1", proof.T1)
+	fs.Append_Point("T2", proof.T2)
+	x := fs.Challenge("x")
+	x_ip, x_j, inv_x_j, s, inv_s, ok := utils.Opt_Challenges(fs, proof.Ip, proof.L, proof.R, n)
+	if !ok {
+		return errors.New("the inner-product argument has a wrong number of rounds")
+	}
+	yN := Generate_Exp_Scalar_Vector(y, n)
+	Inv_yN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), n)
+	w := Generate_Set_Vector(z, statement.Set)
+	z2 := utils.Mul_In_P(z, z)
+	z3 := utils.Mul_In_P(z2, z)
+	x2 := utils.Mul_In_P(x, x)
+
+	// delta = (z+z^2) <1^n, y^n> + z^2 + z <1^n, z^2 1^n + z^3 s>
+	delta := utils.Mul_In_P(utils.Add_In_P(z, z2), utils.Cal_IP_Vec(Generate_Scalar_Vector(big.NewInt(1), n), yN))
+	delta = utils.Add_In_P(delta, z2)
+	delta = utils.Add_In_P(delta, utils.Mul_In_P(z, utils.Cal_IP_Vec(Generate_Scalar_Vector(big.NewInt(1), n), w)))
+
+	// Random weights of the two equations
+	r_1 := utils.Generate_Random_Zp(q)
+	r_2 := utils.Generate_Random_Zp(q)
+	neg_r_2 := utils.Neg_Zp(r_2)
+
+	// Equation (1)
+	ab := utils.Mul_In_P(proof.C_zeta, proof.C_eta)
+	g_exp := utils.Add_In_P(utils.Mul_In_P(r_1, utils.Sub_In_P(proof.Ip, delta)), utils.Mul_In_P(r_2, utils.Mul_In_P(x_ip, utils.Sub_In_P(ab, proof.Ip))))
+	h_exp := utils.Add_In_P(utils.Mul_In_P(r_1, proof.Tau_x), utils.Mul_In_P(r_2, proof.Mu))
+	me.Add_Term(statement.Gen_g, g_exp)
+	me.Add_Term(statement.Gen_h, h_exp)
+	me.Add_Term(statement.Pub_Coin, utils.Neg_Zp(utils.Mul_In_P(r_1, z3)))
+	me.Add_Term(proof.T1, utils.Neg_Zp(utils.Mul_In_P(r_1, x)))
+	me.Add_Term(proof.T2, utils.Neg_Zp(utils.Mul_In_P(r_1, x2)))
+
+	// Equation (2)
+	for i := 0; i < n; i++ {
+		as_z := utils.Sub_In_P(utils.Mul_In_P(proof.C_zeta, s[i]), z)
+		bs := utils.Mul_In_P(utils.Mul_In_P(proof.C_eta, inv_s[i]), Inv_yN[i])
+		bs_z := utils.Sub_In_P(bs, utils.Add_In_P(z, utils.Mul_In_P(w[i], Inv_yN[i])))
+		me.Add_Term(statement.Gen_Vec_G[i], utils.Mul_In_P(r_2, as_z))
+		me.Add_Term(statement.Gen_Vec_H[i], utils.Mul_In_P(r_2, bs_z))
+	}
+	me.Add_Term(proof.A, neg_r_2)
+	me.Add_Term(proof.B, utils.Mul_In_P(neg_r_2, x))
+	me.Add_Terms(proof.L, utils.Cal_Sca_Vec(x_j, neg_r_2))
+	me.Add_Terms(proof.R, utils.Cal_Sca_Vec(inv_x_j, neg_r_2))
+	return nil
+}
@@ -0,0 +1,56 @@
+package membership
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Parameters for sets of up to 8 elements and a set of 5 elements
+func testSet() (Params, []*big.Int) {
+	utils.Curve = secp256k1.S256()
+	var params Params
+	params.Setup(8)
+	return params, []*big.Int{big.NewInt(3), big.NewInt(17), big.NewInt(42), big.NewInt(99), big.NewInt(1000)}
+}
+
+// Copy of a point vector with the i-th point replaced by a fresh one
+func replacePoint(points []utils.Point, i int) []utils.Point {
+	res := append([]utils.Point{}, points...)
+	res[i] = utils.GeneratePoint()
+	return res
+}
+
+// A proof of the statement S1 is rejected for every statement S2 differing in one field
+func TestReplay(t *testing.T) {
+	params, set := testSet()
+	statement, proof, err := params.Prove(set, big.NewInt(42), utils.Generate_Random_Zp(256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(statement, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+
+	mutations := map[string]func(statement *Statement){
+		"g": func(statement *Statement) { statement.Gen_g = utils.GeneratePoint() },
+		"h": func(statement *Statement) { statement.Gen_h = utils.GeneratePoint() },
+		"G": func(statement *Statement) { statement.Gen_Vec_G = replacePoint(statement.Gen_Vec_G, 4) },
+		"H": func(statement *Statement) { statement.Gen_Vec_H = replacePoint(statement.Gen_Vec_H, 0) },
+		"V": func(statement *Statement) { statement.Pub_Coin = utils.GeneratePoint() },
+		"set": func(statement *Statement) {
+			statement.Set = append([]*big.Int{}, statement.Set...)
+			statement.Set[1] = big.NewInt(18)
+		},
+	}
+	for field, mutate := range mutations {
+		replayed := statement
+		mutate(&replayed)
+		if err := Verify(replayed, proof); err == nil {
+			t.Errorf("proof replayed against another %s accepted", field)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package membership
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+const q = 256
+
+// Public parameters of membership proofs, a set of n elements uses the first n generators of each vector
+type Params struct {
+	Gen_g, Gen_h         utils.Point   // generators g,h of the value commitment
+	Gen_Vec_G, Gen_Vec_H []utils.Point // generator vector g h
+}
+
+// Initialization function
+func (params *Params) New(G utils.Point, H utils.Point, G_Vector []utils.Point, H_Vector []utils.Point) {
+	params.Gen_g = G
+	params.Gen_h = H
+	params.Gen_Vec_G = G_Vector
+	params.Gen_Vec_H = H_Vector
+}
+
+// Generate public parameters serving sets of up to max_n elements
+func (params *Params) Setup(max_n int) {
+	params.New(utils.GeneratePoint(), utils.GeneratePoint(), utils.GenerateMultiPoint(max_n), utils.GenerateMultiPoint(max_n))
+}
+
+// Commit to a value: V = g^v h^gamma
+func (params *Params) Commit(value *big.Int, blinding *big.Int) utils.Point {
+	return utils.Pedersen_Commit(params.Gen_g, params.Gen_h, value, blinding)
+}
+
+// Generate a proof that the value committed in V = g^value h^blinding equals one element of the public set,
+// without revealing which one
+func (params *Params) Prove(set []*big.Int, value *big.Int, blinding *big.Int) (Statement, Proof, error) {
+	n := len(set)
+	if n == 0 {
+		return Statement{}, Proof{}, errors.New("the set should not be empty")
+	}
+	if len(params.Gen_Vec_G) < n || len(params.Gen_Vec_H) < n {
+		return Statement{}, Proof{}, errors.New("the generator vectors are shorter than the set")
+	}
+	if !utils.Is_Valid_Scalar(value) || !utils.Is_Valid_Scalar(blinding) {
+		return Statement{}, Proof{}, errors.New("the value and the blinding factor should be elements of Zp")
+	}
+	l := -1
+	for i := range set {
+		if !utils.Is_Valid_Scalar(set[i]) {
+			return Statement{}, Proof{}, errors.New("the set elements should be elements of Zp")
+		}
+		if l < 0 && set[i].Cmp(value) == 0 {
+			l = i
+		}
+	}
+	if l < 0 {
+		return Statement{}, Proof{}, errors.New("the value is not an element of the set")
+	}
+	statement, proof := params.proveIndex(set, l, value, blinding)
+	return statement, proof, nil
+}
+
+////////////////////////Private functions
+
+// Prove that the one-hot vector selecting index l opens V = g^{s_l} h^gamma
+func (params *Params) proveIndex(set []*big.Int, l int, value *big.Int, gamma *big.Int) (Statement, Proof) {
+	n := len(set)
+	Vec_G := params.Gen_Vec_G[:n]
+	Vec_H := params.Gen_Vec_H[:n]
+	statement := Statement{
+		Gen_g:     params.Gen_g,
+		Gen_h:     params.Gen_h,
+		Gen_Vec_G: params.Gen_Vec_G,
+		Gen_Vec_H: params.Gen_Vec_H,
+		Pub_Coin:  params.Commit(value, gamma),
+		Set:       set,
+	}
+
+	// Hedge the nonces with the opening and the statement
+	fs := statement.newTranscript()
+	nonces := utils.Hedged_Nonce_Source(*fs, []*big.Int{value, gamma})
+
+	//Generate commitments A, B
+	b_0 := Generate_b_0(l, n)
+	b_1 := Generate_b_1(b_0)
+	s_0 := nonces.Scalar_Vector(n)
+	s_1 := nonces.Scalar_Vector(n)
+	alpha := nonces.Scalar()
+	beta := nonces.Scalar()
+	A := utils.Cal_Point_Add(utils.Pedersen_Commit_Vector(Vec_G, Vec_H, b_0, b_1), utils.Commit(params.Gen_h, alpha))
+	B := utils.Cal_Point_Add(utils.Pedersen_Commit_Vector(Vec_G, Vec_H, s_0, s_1), utils.Commit(params.Gen_h, beta))
+
+	//Generate challenges y,z
+	fs.Append_Point("A", A)
+	fs.Append_Point("B", B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+
+	// l(x) = b0 + z1^n + s0 x, r(x) = y^n \circ (b1 + z1^n + s1 x) + z^2 1^n + z^3 s
+	yN := Generate_Exp_Scalar_Vector(y, n)
+	z1N := Generate_Scalar_Vector(z, n)
+	l_0 := utils.Cal_Add_Vec(b_0, z1N)
+	r_0 := utils.Cal_Add_Vec(utils.Cal_HP_Vec(yN, utils.Cal_Add_Vec(b_1, z1N)), Generate_Set_Vector(z, set))
+	r_1 := utils.Cal_HP_Vec(yN, s_1)
+
+	//Compute T1, T2 with t1 = <l_0, r_1> + <s_0, r_0>, t2 = <s_0, r_1>
+	t_1 := utils.Add_In_P(utils.Cal_IP_Vec(l_0, r_1), utils.Cal_IP_Vec(s_0, r_0))
+	t_2 := utils.Cal_IP_Vec(s_0, r_1)
+	tau_1 := nonces.Scalar()
+	tau_2 := nonces.Scalar()
+	T1 := utils.Pedersen_Commit(params.Gen_g, params.Gen_h, t_1, tau_1)
+	T2 := utils.Pedersen_Commit(params.Gen_g, params.Gen_h, t_2, tau_2)
+
+	//Generate challenge x
+	fs.Append_Point("T1", T1)
+	fs.Append_Point("T2", T2)
+	x := fs.Challenge("x")
+
+	//Compute zeta = l(x), eta = r(x) and t = <zeta, eta>
+	zeta := utils.Cal_Add_Vec(l_0, utils.Cal_Sca_Vec(s_0, x))
+	eta := utils.Cal_Add_Vec(r_0, utils.Cal_Sca_Vec(r_1, x))
+	ip := utils.Cal_IP_Vec(zeta, eta)
+
+	//Compute tau_x = tau_1 x + tau_2 x^2 + z^3 gamma and mu = alpha + beta x
+	z3 := utils.Mul_In_P(z, utils.Mul_In_P(z, z))
+	tau_x := utils.Add_In_P(utils.Mul_In_P(tau_1, x), utils.Mul_In_P(tau_2, utils.Mul_In_P(x, x)))
+	tau_x = utils.Add_In_P(tau_x, utils.Mul_In_P(z3, gamma))
+	mu := utils.Add_In_P(alpha, utils.Mul_In_P(beta, x))
+
+	// Compress the openings over generators g and h^{y^{-n}}
+	Inv_yN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), n)
+	Inv_Vec_H := utils.Generate_Point_Vector_with_y(Vec_H, Inv_yN)
+	L, R, C_zeta, C_eta := utils.Opt_Prove(fs, zeta, eta, Vec_G, Inv_Vec_H, params.Gen_g)
+
+	proof := Proof{
+		A:      A,
+		B:      B,
+		T1:     T1,
+		T2:     T2,
+		Tau_x:  tau_x,
+		Mu:     mu,
+		Ip:     ip,
+		L:      L,
+		R:      R,
+		C_zeta: C_zeta[0],
+		C_eta:  C_eta[0],
+	}
+	return statement, proof
+}
+
+// Start the Fiat-Shamir transcript of a statement, binding the generators, the first n generators of each
+// vector, the commitment and the set
+func (statement *Statement) newTranscript() *utils.Fiat_Shamir {
+	var fs utils.Fiat_Shamir
+	n := len(statement.Set)
+	fs.New("membership")
+	fs.Append_Point("g", statement.Gen_g)
+	fs.Append_Point("h", statement.Gen_h)
+	fs.Append_Points("G", utils.Point_Prefix(statement.Gen_Vec_G, n))
+	fs.Append_Points("H", utils.Point_Prefix(statement.Gen_Vec_H, n))
+	fs.Append_Point("V", statement.Pub_Coin)
+	fs.Append_Int("n", len(statement.Set))
+	for _, element := range statement.Set {
+		fs.Append_Scalar("s", element)
+	}
+	return &fs
+}
@@ -0,0 +1,94 @@
+package membership
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+)
+
+func TestMembership(t *testing.T) {
+	params, set := testSet()
+	for i, value := range set {
+		statement, proof, err := params.Prove(set, value, utils.Generate_Random_Zp(256))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(statement, proof); err != nil {
+			t.Errorf("honest proof for element %d rejected: %v", i, err)
+		}
+	}
+
+	// Only the first n generators belong to the statement
+	statement, proof, err := params.Prove(set, set[0], utils.Generate_Random_Zp(256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement.Gen_Vec_G = replacePoint(statement.Gen_Vec_G, len(set))
+	if err := Verify(statement, proof); err != nil {
+		t.Errorf("proof rejected for a change beyond the first n generators: %v", err)
+	}
+}
+
+// A prover selecting an element different from the committed value cannot convince the verifier
+func TestMembershipOutsideSet(t *testing.T) {
+	params, set := testSet()
+	if _, _, err := params.Prove(set, big.NewInt(43), utils.Generate_Random_Zp(256)); err == nil {
+		t.Fatal("proof generated for a value outside the set")
+	}
+	for l := range set {
+		statement, proof := params.proveIndex(set, l, big.NewInt(43), utils.Generate_Random_Zp(256))
+		if err := Verify(statement, proof); err == nil {
+			t.Errorf("proof of a value outside the set accepted for index %d", l)
+		}
+	}
+}
+
+// A proof for one set is rejected for another set that also contains the committed value
+func TestMembershipWrongSet(t *testing.T) {
+	params, set := testSet()
+	statement, proof, err := params.Prove(set, set[2], utils.Generate_Random_Zp(256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sets := map[string][]*big.Int{
+		"reordered": {set[2], set[0], set[1], set[3], set[4]},
+		"replaced":  {set[0], set[1], set[2], set[3], big.NewInt(7)},
+		"shorter":   set[:4],
+	}
+	for name, other := range sets {
+		replayed := statement
+		replayed.Set = other
+		if err := Verify(replayed, proof); err == nil {
+			t.Errorf("proof accepted for a %s set", name)
+		}
+	}
+}
+
+func TestMembershipTamperedRounds(t *testing.T) {
+	params, set := testSet()
+	statement, proof, err := params.Prove(set, set[1], utils.Generate_Random_Zp(256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := len(proof.L) - 1
+	tamperings := map[string]func(proof *Proof){
+		"swapped":   func(proof *Proof) { proof.L[0], proof.R[0] = proof.R[0], proof.L[0] },
+		"L":         func(proof *Proof) { proof.L[last] = utils.GeneratePoint() },
+		"R":         func(proof *Proof) { proof.R[0] = utils.Cal_Point_Add(proof.R[0], statement.Gen_g) },
+		"truncated": func(proof *Proof) { proof.L, proof.R = proof.L[:last], proof.R[:last] },
+		"reordered": func(proof *Proof) { proof.L[0], proof.L[last] = proof.L[last], proof.L[0] },
+	}
+	for name, tamper := range tamperings {
+		tampered := proof
+		tampered.L = append([]utils.Point{}, proof.L...)
+		tampered.R = append([]utils.Point{}, proof.R...)
+		tamper(&tampered)
+		if err := Verify(statement, tampered); err == nil {
+			t.Errorf("proof with %s rounds accepted", name)
+		}
+	}
+	if err := Verify(statement, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+}
@@ -1,7 +1,7 @@
 package omniring
 
 import (
-	"crypto/sha256"
+	"errors"
 	"math/big"
 
 	"anyOutOfMany/utils"
@@ -31,21 +31,30 @@ func Generate_b_0(k int, N int) ([]*big.Int, error) {
 
 	var b_0 []*big.Int
 
+	n := N / k
+	//Ensure the k selected ring members are distinct
+	if k > n {
+		return nil, errors.New("the secret number should not be bigger than the ring size")
+	}
+
 	//generate a zero vector
 	for j := 0; j < N; j++ {
 		b_0 = append(b_0, big.NewInt(0))
 	}
 
-	n := N / k
-	counter := 0
-	//Generate N-length binary vector with one "1" in each n-length subvector
+	//Generate N-length binary vector with one "1" in each n-length subvector, at distinct ring positions
+	used := make([]bool, n)
 	for i := 0; i < k; i++ {
 		temp := utils.Generate_Random_Zp(q)
 		var rand big.Int
 		rand.Mod(temp, big.NewInt(int64(n)))
 		index := rand.Int64()
-		b_0[index+int64(counter*n)] = big.NewInt(1)
-		counter++
+		if used[index] {
+			i = i - 1
+			continue
+		}
+		used[index] = true
+		b_0[index+int64(i*n)] = big.NewInt(1)
 	}
 	return b_0, nil
 }
@@ -118,68 +127,140 @@ func Generate_neg_z_Vector(z byte, n int) []*big.Int {
 	return zn
 }
 
+//Generate the n public keys of the ring, the member selected by the j-th subvector of b_0 holding the j-th secret key
 func (prover *Prover) Generate_Multi_Public_Key(k, N int, bit []*big.Int) []utils.Point {
 	var public_key []utils.Point
-	var num1, num2 int
 	n := N / k
-	fake_secret_key := utils.Generate_Random_Zp_Vector(int(N-k), q)
-	for i := 0; i < int(n); i++ {
-		if bit[i].Cmp(big.NewInt(0)) == 0 {
-			secret_key := fake_secret_key[num1]
-			num1++
-			public_key = append(public_key, utils.Commit(prover.Gen_H, secret_key))
-		}
-		if bit[i].Cmp(big.NewInt(1)) == 0 {
-			secret_key := prover.sec_Vec_Key[num2]
-			num2++
-			public_key = append(public_key, utils.Commit(prover.Gen_H, secret_key))
+	for i := 0; i < n; i++ {
+		secret_key := utils.Generate_Random_Zp(q)
+		for j := 0; j < k; j++ {
+			if bit[j*n+i].Cmp(big.NewInt(1)) == 0 {
+				secret_key = prover.sec_Vec_Key[j]
+			}
 		}
+		public_key = append(public_key, utils.Commit(prover.Gen_H, secret_key))
 	}
 	return public_key
 }
 
+//Generate the n input coins of the ring, the member selected by the j-th subvector of b_0 holding the j-th value
 func (prover *Prover) Generate_Multi_Public_Coin(k, N int, bit []*big.Int) []utils.Point {
 	var public_coin []utils.Point
-	num1, num2 := 0, 0
-	fake_secret_value := utils.Generate_Random_Zp_Vector(int(N-k), q)
-	fake_secret_random := utils.Generate_Random_Zp_Vector(int(N-k), q)
-	for i := 0; i < int(N); i++ {
-		if bit[i].Cmp(big.NewInt(0)) == 0 {
-			secret_key := fake_secret_value[num1]
-			secret_random := fake_secret_random[num1]
-			public_coin = append(public_coin, utils.Pedersen_Commit(prover.Gen_G, prover.Gen_H, secret_key, secret_random))
-			num1++
-		}
-		if bit[i].Cmp(big.NewInt(1)) == 0 {
-			secret_key := prover.sec_Vec_Key[num2]
-			secret_random := prover.sec_Vec_Random[num2]
-			public_coin = append(public_coin, utils.Pedersen_Commit(prover.Gen_G, prover.Gen_H, secret_key, secret_random))
-			num2++
+	n := N / k
+	for i := 0; i < n; i++ {
+		secret_value := utils.Generate_Random_Zp(prover.d)
+		secret_random := utils.Generate_Random_Zp(q)
+		for j := 0; j < k; j++ {
+			if bit[j*n+i].Cmp(big.NewInt(1)) == 0 {
+				secret_value = prover.sec_Vec_Value[j]
+				secret_random = prover.sec_Vec_Random[j]
+			}
 		}
+		public_coin = append(public_coin, utils.Pedersen_Commit(prover.Gen_G, prover.Gen_H, secret_value, secret_random))
 	}
 	return public_coin
 }
 
-// Generate challenge
-func Generate_YZ(A utils.Point, B utils.Point) (*big.Int, *big.Int) {
-	AB1 := utils.Cal_Point_Add(A, utils.Cal_Point_Sca(B, big.NewInt(1)))
-	AB2 := utils.Cal_Point_Add(A, utils.Cal_Point_Sca(B, big.NewInt(2)))
-	y32 := sha256.Sum256(AB1.Point2Bytes())
-	y := big.NewInt(0).SetBytes(y32[:])
-	z32 := sha256.Sum256(AB2.Point2Bytes())
-	z := big.NewInt(0).SetBytes(z32[:])
-	return y, z
+//Generate Y = Pk \circ Coin^u
+func Generate_Vec_Y(keys []utils.Point, coins []utils.Point, u *big.Int) []utils.Point {
+	return utils.Cal_Point_Add_Vec(keys, utils.Cal_Point_Sca_Vec(coins, u))
+}
+
+//Generate the generator vector G_w = (P_0 G^w, P_1 H^w, P_{2+i} Y_i^w, G), where G_0 commits to the same
+//value as G_w for the vector c_L since G^{c_0} H^{c_1} Y^{e} is the identity
+func Generate_Vec_Gw(G utils.Point, H utils.Point, Y []utils.Point, P_Vector []utils.Point, G_Vector []utils.Point, w *big.Int) []utils.Point {
+	var Gen_Vec_Gw []utils.Point
+	Gen_Vec_Gw = append(Gen_Vec_Gw, P_Vector[:2+len(Y)]...)
+	if w.Sign() != 0 {
+		var temp []utils.Point
+		temp = append(temp, utils.Commit(G, w))
+		temp = append(temp, utils.Commit(H, w))
+		temp = append(temp, utils.Cal_Point_Sca_Vec(Y, w)...)
+		Gen_Vec_Gw = utils.Cal_Point_Add_Vec(Gen_Vec_Gw, temp)
+	}
+	return append(Gen_Vec_Gw, G_Vector...)
 }
 
-func Generate_X(T1 utils.Point, T2 utils.Point) *big.Int {
-	T1T2 := utils.Cal_Point_Add(T1, T2)
-	x32 := sha256.Sum256(T1T2.Point2Bytes())
-	x := big.NewInt(0).SetBytes(x32[:])
-	return x
+//Generate the constraint vectors theta, alpha, mu and the constant delta for the witness
+//c_L = (c_0, c_1, e, b_0, a, r, sk) and c_R = (0, 0, 0^n, b_0 - 1^N, 0^k, 0^k, sk^{-1}) such that
+//<c_L + alpha, theta \circ c_R + mu> = delta - z^7 <1, a_out>. The constraints are weighted by powers of z:
+//(1) <c_L, theta \circ c_R> = <1^k, y^k> on sk, i.e., b_0 is binary and sk is invertible
+//(2) c_R is zero outside b_0 and sk
+//(3) every subvector of b_0 selects one member
+//(4) c_0 = -u <a, v^k>, (5) c_1 = -<u r + sk, v^k>, (6) e = \sum v^j b_0,j
+//(7) the input and output values are balanced, (8) c_R = c_L - 1 on b_0
+func Generate_Constraints(u *big.Int, v *big.Int, y *big.Int, z *big.Int, k int, n int, N int) ([]*big.Int, []*big.Int, []*big.Int, *big.Int) {
+	size := 2 + n + N + 3*k
+	pos_b, pos_a := 2+n, 2+n+N
+	pos_r, pos_s := pos_a+k, pos_a+2*k
+
+	var vec_v [9][]*big.Int
+	for i := 0; i < 9; i++ {
+		vec_v[i] = Generate_cons_vec(size, big.NewInt(0))
+	}
+	vec_theta := Generate_Exp_Scalar_Vector(y, size)
+	vec_yk := Generate_Exp_Scalar_Vector(y, k)
+	vec_yn := Generate_Exp_Scalar_Vector(y, n)
+	vec_yN := Generate_Exp_Scalar_Vector(y, N)
+	vec_vk := Generate_Exp_Scalar_Vector(v, k)
+	vec_uvk := utils.Cal_Sca_Vec(vec_vk, u)
+
+	copy(vec_v[2], vec_theta)
+	for i := pos_b; i < pos_a; i++ {
+		vec_v[2][i] = big.NewInt(0)
+	}
+	for j := 0; j < k; j++ {
+		vec_v[2][pos_s+j] = big.NewInt(0)
+		for i := 0; i < n; i++ {
+			vec_v[3][pos_b+j*n+i] = vec_yk[j]
+			vec_v[6][pos_b+j*n+i] = utils.Mul_In_P(vec_vk[j], vec_yn[i])
+		}
+		vec_v[4][pos_a+j] = vec_uvk[j]
+		vec_v[5][pos_r+j] = vec_uvk[j]
+		vec_v[5][pos_s+j] = vec_vk[j]
+		vec_v[7][pos_a+j] = utils.Neg_Zp(big.NewInt(1))
+	}
+	vec_v[4][0] = big.NewInt(1)
+	vec_v[5][1] = big.NewInt(1)
+	copy(vec_v[6][2:pos_b], utils.Cal_Neg_Vec(vec_yn))
+	copy(vec_v[8][pos_b:pos_a], vec_yN)
+
+	// mu = \sum_{i=3}^{8} z^i v_i weights the constraints on c_L, omega = z^2 v_2 - z^8 v_8 those on c_R
+	z2 := utils.Mul_In_P(z, z)
+	vec_mu := Generate_cons_vec(size, big.NewInt(0))
+	temp := utils.Mul_In_P(z2, z)
+	for i := 3; i < 9; i++ {
+		vec_mu = utils.Cal_Add_Vec(vec_mu, utils.Cal_Sca_Vec(vec_v[i], temp))
+		temp = utils.Mul_In_P(temp, z)
+	}
+	z8 := utils.Mul_In_P(utils.Mul_In_P(z2, z2), utils.Mul_In_P(z2, z2))
+	vec_omega := utils.Cal_Sub_Vec(utils.Cal_Sca_Vec(vec_v[2], z2), utils.Cal_Sca_Vec(vec_v[8], z8))
+	vec_alpha := utils.Cal_HP_Vec(utils.Cal_Inv_Vec(vec_theta), vec_omega)
+
+	// delta = <1^k, y^k on sk> + z^3 <1^k, y^k> + z^8 <1^N, y^N> + <alpha, mu>
+	vec_1k := Generate_cons_vec(k, big.NewInt(1))
+	vec_1N := Generate_cons_vec(N, big.NewInt(1))
+	delta := utils.Cal_IP_Vec(vec_1k, vec_theta[pos_s:])
+	delta = utils.Add_In_P(delta, utils.Mul_In_P(utils.Mul_In_P(z2, z), utils.Cal_IP_Vec(vec_1k, vec_yk)))
+	delta = utils.Add_In_P(delta, utils.Mul_In_P(z8, utils.Cal_IP_Vec(vec_1N, vec_yN)))
+	delta = utils.Add_In_P(delta, utils.Cal_IP_Vec(vec_alpha, vec_mu))
+	return vec_theta, vec_alpha, vec_mu, delta
 }
 
-func Generate_W(A utils.Point) *big.Int {
-	x32 := sha256.Sum256(A.Point2Bytes())
-	x := big.NewInt(0).SetBytes(x32[:])
-	return x
+//Start the Fiat-Shamir transcript of an omniring statement
+func newTranscript(u *big.Int, v *big.Int, F utils.Point, G utils.Point, H utils.Point, P_Vector []utils.Point, G_Vector []utils.Point, H_Vector []utils.Point, keys []utils.Point, inputs []utils.Point, outputs []utils.Point, k int, N int) *utils.Fiat_Shamir {
+	var fs utils.Fiat_Shamir
+	fs.New("omniring")
+	fs.Append_Int("k", k)
+	fs.Append_Int("N", N)
+	fs.Append_Scalar("u", u)
+	fs.Append_Scalar("v", v)
+	fs.Append_Points("gens", []utils.Point{F, G, H})
+	fs.Append_Points("P", P_Vector)
+	fs.Append_Points("G", G_Vector)
+	fs.Append_Points("H", H_Vector)
+	fs.Append_Points("keys", keys)
+	fs.Append_Points("inputs", inputs)
+	fs.Append_Points("outputs", outputs)
+	return &fs
 }
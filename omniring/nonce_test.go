@@ -0,0 +1,57 @@
+package omniring
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Copy of a point vector with the i-th point replaced by a fresh one
+func replacePoint(points []utils.Point, i int) []utils.Point {
+	res := append([]utils.Point{}, points...)
+	res[i] = utils.GeneratePoint()
+	return res
+}
+
+// Changing any field of the statement changes the deterministic nonces of the same witness
+func TestNonceBindsStatement(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	k, N, d := 2, 8, 64
+	n := N / k
+	var base Prover
+	base.New(utils.Generate_Random_Zp(d), utils.Generate_Random_Zp(d), utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint(),
+		utils.GenerateMultiPoint(2+N), utils.GenerateMultiPoint(3*k+N), utils.GenerateMultiPoint(2+n+3*k+N), k, N, d)
+	first := func(prover Prover) string {
+		prover.Deterministic([]byte("aux"))
+		prover.bindNonces()
+		return prover.nonces.Scalar().String()
+	}
+	nonce := first(base)
+	if first(base) != nonce {
+		t.Fatal("the nonces are not deterministic")
+	}
+
+	mutations := map[string]func(prover *Prover){
+		"u":       func(prover *Prover) { prover.u = new(big.Int).Add(prover.u, big.NewInt(1)) },
+		"v":       func(prover *Prover) { prover.v = new(big.Int).Add(prover.v, big.NewInt(1)) },
+		"F":       func(prover *Prover) { prover.Gen_F = utils.GeneratePoint() },
+		"G":       func(prover *Prover) { prover.Gen_G = utils.GeneratePoint() },
+		"H":       func(prover *Prover) { prover.Gen_H = utils.GeneratePoint() },
+		"P":       func(prover *Prover) { prover.Gen_Vec_P = replacePoint(prover.Gen_Vec_P, 1) },
+		"G_Vec":   func(prover *Prover) { prover.Gen_Vec_G = replacePoint(prover.Gen_Vec_G, 0) },
+		"H_Vec":   func(prover *Prover) { prover.Gen_Vec_H = replacePoint(prover.Gen_Vec_H, len(prover.Gen_Vec_H)-1) },
+		"keys":    func(prover *Prover) { prover.Pub_Vec_Key = replacePoint(prover.Pub_Vec_Key, 0) },
+		"inputs":  func(prover *Prover) { prover.Inp_Vec_Coin = replacePoint(prover.Inp_Vec_Coin, 0) },
+		"outputs": func(prover *Prover) { prover.Out_Vec_Coin = replacePoint(prover.Out_Vec_Coin, 0) },
+	}
+	for field, mutate := range mutations {
+		prover := base
+		mutate(&prover)
+		if first(prover) == nonce {
+			t.Errorf("the nonces do not depend on %s", field)
+		}
+	}
+}
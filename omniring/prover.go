@@ -1,7 +1,6 @@
 package omniring
 
 import (
-	"math"
 	"math/big"
 
 	"anyOutOfMany/utils"
@@ -13,6 +12,7 @@ type Prover struct {
 	Gen_F, Gen_G, Gen_H             utils.Point   // generators u,v
 	Gen_Vec_G, Gen_Vec_H, Gen_Vec_P []utils.Point // generator vector g h
 	Gen_Vec_Gw                      []utils.Point
+	Gen_Vec_Hy                      []utils.Point // generator vector h^{theta^{-1}} of the openings eta
 	Pub_Vec_Key                     []utils.Point // public key vector i.e., ring set
 	Out_Vec_Coin, Inp_Vec_Coin      []utils.Point
 	A, B                            utils.Point   // commitments A, B
@@ -32,7 +32,6 @@ type Prover struct {
 	/////////////////////////////////private parameters:
 	sec_Vec_Key, sec_Vec_Value, sec_Vec_Random []*big.Int // secrets
 	out_Vec_Value, out_Vec_Random              []*big.Int
-	vec_inv_theta                              []*big.Int
 
 	//binary vector and corresponding randomness
 	b_0 []*big.Int
@@ -47,6 +46,9 @@ type Prover struct {
 	alpha    *big.Int
 	beta     *big.Int
 
+	// source of the nonces, crypto/rand if nil
+	nonces *utils.Nonce_Source
+
 	// intemediate values zeta, eta, t1, t2 and corresponding randomness
 	tau_1 *big.Int
 	tau_2 *big.Int
@@ -74,52 +76,38 @@ func (prover *Prover) New(u *big.Int, v *big.Int, F utils.Point, G utils.Point,
 	prover.d = d
 
 	//Generate secrets of prover
-	prover.sec_Vec_Key = utils.Generate_Random_Zp_Vector(k, prover.d)
-	prover.generateKey()
+	prover.sec_Vec_Key = utils.Generate_Random_Zp_Vector(k, q)
 	prover.sec_Vec_Value = utils.Generate_Random_Zp_Vector(k, prover.d)
-	prover.sec_Vec_Random = utils.Generate_Random_Zp_Vector(k, prover.d)
-	if len(prover.sec_Vec_Value) > 1 {
-		prover.out_Vec_Value = utils.Cal_Add_Vec(prover.sec_Vec_Value[:len(prover.sec_Vec_Value)/2], prover.sec_Vec_Value[len(prover.sec_Vec_Value)/2:]) //Simulate the transaction between input and output
-		prover.out_Vec_Random = utils.Generate_Random_Zp_Vector(k/2, prover.d)
-		prover.generateCoin()
-	} else {
-		prover.out_Vec_Value = prover.sec_Vec_Value
-		prover.out_Vec_Random = utils.Generate_Random_Zp_Vector(1, prover.d)
-		prover.generateCoin()
+	prover.sec_Vec_Random = utils.Generate_Random_Zp_Vector(k, q)
+	prover.generateKey()
+	//Simulate the transaction between input and output, pairing the inputs and keeping the last one if k is odd
+	half := k / 2
+	prover.out_Vec_Value = utils.Cal_Add_Vec(prover.sec_Vec_Value[:half], prover.sec_Vec_Value[half:2*half])
+	if k%2 == 1 {
+		prover.out_Vec_Value = append(prover.out_Vec_Value, prover.sec_Vec_Value[k-1])
 	}
+	prover.out_Vec_Random = utils.Generate_Random_Zp_Vector(len(prover.out_Vec_Value), q)
+	prover.generateCoin()
 }
 
 ////////////////////////Public interfaces
 
-//Get response zeta, eta, t, tau_x, mu, f_s
+//Derive the nonces deterministically from the secrets, the statement and the optional extra randomness aux
+func (prover *Prover) Deterministic(aux []byte) {
+	prover.nonces = utils.New_Nonce_Source(aux)
+}
+
+//Get response zeta, eta, t, tau_x, mu, together with the generator vectors G_w and h^{theta^{-1}} of the openings
 func (prover *Prover) GenerateRsp() ([]utils.Point, []utils.Point, []utils.Point, Transcript, []utils.Point, []utils.Point) {
+	prover.bindNonces()
+	fs := newTranscript(prover.u, prover.v, prover.Gen_F, prover.Gen_G, prover.Gen_H, prover.Gen_Vec_P, prover.Gen_Vec_G, prover.Gen_Vec_H, prover.Pub_Vec_Key, prover.Inp_Vec_Coin, prover.Out_Vec_Coin, prover.k, prover.N)
 
 	//prover computes Commitments A, B
-	prover.calculateRound1()
+	prover.calculateRound1(fs)
 
 	//prover compute Commitments T1, T2
+	prover.calculateRound2(fs)
 
-	prover.calculateRound2()
-
-	theta_eta := utils.Cal_HP_Vec(prover.vec_inv_theta, prover.eta)
-
-	// Padding the vectors to 2^n length
-	deg := math.Ceil(math.Log2(float64(len(prover.c_L))))
-	pad := math.Pow(2, deg) - float64(len(prover.c_L))
-	zero_vec := Generate_cons_vec(int(pad), big.NewInt(0))
-	var one_vec []utils.Point
-	for i := 0; i < len(zero_vec); i++ {
-		one_vec = append(one_vec, utils.Commit(prover.Gen_F, big.NewInt(0)))
-	}
-	prover.zeta = append(prover.zeta, zero_vec...)
-	theta_eta = append(theta_eta, zero_vec...)
-	prover.Gen_Vec_Gw = append(prover.Gen_Vec_Gw, one_vec...)
-	prover.Gen_Vec_H = append(prover.Gen_Vec_H, one_vec...)
-
-	// Compress the openings
-	// prover.L, prover.R, prover.c_zeta, prover.c_eta = opt_prove(prover.zeta, theta_eta, prover.Gen_Vec_Gw, prover.Gen_Vec_H, prover.Gen_G)
-
-	//prover.TestCorrect()
 	transcript := Transcript{
 		A:     prover.A,
 		B:     prover.B,
@@ -129,13 +117,13 @@ func (prover *Prover) GenerateRsp() ([]utils.Point, []utils.Point, []utils.Point
 		Mu:    prover.mu,
 		Ip:    prover.ip,
 		Zeta:  prover.zeta,
-		Eta:   theta_eta,
+		Eta:   prover.eta,
 		W:     prover.w,
 		X:     prover.x,
 		Y:     prover.y,
 		Z:     prover.z,
 	}
-	return prover.Pub_Vec_Key, prover.Inp_Vec_Coin, prover.Out_Vec_Coin, transcript, prover.Gen_Vec_Gw, prover.Gen_Vec_H
+	return prover.Pub_Vec_Key, prover.Inp_Vec_Coin, prover.Out_Vec_Coin, transcript, prover.Gen_Vec_Gw, prover.Gen_Vec_Hy
 }
 
 ////////////////////////Private functions
@@ -160,48 +148,61 @@ func (prover *Prover) generateCoin() {
 	}
 }
 
-//Generate Commitments A
-func (prover *Prover) calculateRound1() {
-	// Generate commitment Y
-	Coin_u := utils.Cal_Point_Sca_Vec(prover.Inp_Vec_Coin, prover.u) // Coin^u
-	Gen_Vec_Y := utils.Cal_Point_Add_Vec(prover.Pub_Vec_Key, Coin_u) // Y = Pk \circ Coin^u
-
-	// Generate G_0, i.e., G_w with w=0
-	G0 := utils.Commit(prover.Gen_G, big.NewInt(0))
-	H0 := utils.Commit(prover.Gen_G, big.NewInt(0))
-	zero_vec := Generate_cons_vec(prover.n, big.NewInt(0))
-	var Gen_Vec_Y0 []utils.Point
-	for i := 0; i < len(zero_vec); i++ {
-		Gen_Vec_Y0 = append(Gen_Vec_Y0, utils.Commit(Gen_Vec_Y[i], zero_vec[i]))
+//Restart the nonces from the secrets and the statement
+func (prover *Prover) bindNonces() {
+	if prover.nonces == nil {
+		return
+	}
+	fs := newTranscript(prover.u, prover.v, prover.Gen_F, prover.Gen_G, prover.Gen_H, prover.Gen_Vec_P, prover.Gen_Vec_G, prover.Gen_Vec_H, prover.Pub_Vec_Key, prover.Inp_Vec_Coin, prover.Out_Vec_Coin, prover.k, prover.N)
+	fs.Append_Bytes("phase", []byte("nonces"))
+	var secrets []*big.Int
+	for _, vec := range [][]*big.Int{prover.b_0, prover.sec_Vec_Key, prover.sec_Vec_Value, prover.sec_Vec_Random, prover.out_Vec_Random} {
+		secrets = append(secrets, vec...)
+	}
+	prover.nonces.Bind(*fs, secrets)
+}
+
+//Draw a nonce, of width d when no nonce source is set
+func (prover *Prover) nonce() *big.Int {
+	if prover.nonces == nil {
+		return utils.Generate_Random_Zp(prover.d)
+	}
+	return prover.nonces.Scalar()
+}
+
+//Draw n nonces
+func (prover *Prover) nonceVector(n int) []*big.Int {
+	var res []*big.Int
+	for i := 0; i < n; i++ {
+		res = append(res, prover.nonce())
 	}
+	return res
+}
 
-	var temp []utils.Point
-	temp = append(temp, G0)
-	temp = append(temp, H0)
-	temp = append(temp, Gen_Vec_Y0...)
-	temp = utils.Cal_Point_Add_Vec(temp, prover.Gen_Vec_P)
-	Gen_Vec_G0 := append(temp, prover.Gen_Vec_G...)
+//Generate Commitments A, B
+func (prover *Prover) calculateRound1(fs *utils.Fiat_Shamir) {
+	n := prover.n
+	k := prover.k
+	Gen_Vec_Y := Generate_Vec_Y(prover.Pub_Vec_Key, prover.Inp_Vec_Coin, prover.u)
+	Gen_Vec_G0 := Generate_Vec_Gw(prover.Gen_G, prover.Gen_H, Gen_Vec_Y, prover.Gen_Vec_P, prover.Gen_Vec_G, big.NewInt(0))
 
 	//////////////////////////////////////////////Generate commitment A
-	prover.r_A = utils.Generate_Random_Zp(prover.d)
+	prover.r_A = prover.nonce()
 
-	//Generate c_L
-	v_k := Generate_Exp_Scalar_Vector(prover.v, prover.k)
+	//Generate c_L = (c_0, c_1, e, b_0, a, r, sk) with G^{c_0} H^{c_1} Y^e the identity
+	v_k := Generate_Exp_Scalar_Vector(prover.v, k)
 	u_a := utils.Cal_Sca_Vec(prover.sec_Vec_Value, prover.u)
-	c_L_1 := utils.Cal_IP_Vec(u_a, v_k) // zeta
+	c_L_1 := utils.Neg_Zp(utils.Cal_IP_Vec(u_a, v_k))
 	u_r := utils.Cal_Sca_Vec(prover.sec_Vec_Random, prover.u)
 	u_r_x := utils.Cal_Add_Vec(u_r, prover.sec_Vec_Key)
-	c_L_2 := utils.Cal_IP_Vec(u_r_x, v_k) //eta
+	c_L_2 := utils.Neg_Zp(utils.Cal_IP_Vec(u_r_x, v_k))
 
-	vk_E := Generate_cons_vec(prover.n, big.NewInt(0))
-	counter := 0
-	var res []*big.Int
-	for i := 0; i < prover.k; i++ {
-		res = utils.Cal_Sca_Vec(prover.b_0[(0+counter*prover.n):((counter+1)*prover.n)], v_k[i])
-		vk_E = utils.Cal_Add_Vec(vk_E, res)
-		counter++
+	vk_E := Generate_cons_vec(n, big.NewInt(0))
+	for i := 0; i < k; i++ {
+		vk_E = utils.Cal_Add_Vec(vk_E, utils.Cal_Sca_Vec(prover.b_0[i*n:(i+1)*n], v_k[i]))
 	}
 
+	prover.c_L = nil
 	prover.c_L = append(prover.c_L, c_L_1)
 	prover.c_L = append(prover.c_L, c_L_2)
 	prover.c_L = append(prover.c_L, vk_E...)
@@ -210,189 +211,74 @@ func (prover *Prover) calculateRound1() {
 	prover.c_L = append(prover.c_L, prover.sec_Vec_Random...)
 	prover.c_L = append(prover.c_L, prover.sec_Vec_Key...)
 
-	//Generate c_R
-	vec_zero := Generate_cons_vec(2+prover.n, big.NewInt(0))
+	//Generate c_R = (0, 0, 0^n, b_0 - 1^N, 0^k, 0^k, sk^{-1})
 	vec_one := Generate_cons_vec(prover.N, big.NewInt(1))
-	b_0_1 := utils.Cal_Sub_Vec(prover.b_0, vec_one)
-	vec_zero_2 := Generate_cons_vec(2*prover.k, big.NewInt(0))
-	inv_sec_key := utils.Cal_Inv_Vec(prover.sec_Vec_Key)
-
-	prover.c_R = append(prover.c_R, vec_zero...)
-	prover.c_R = append(prover.c_R, b_0_1...)
-	prover.c_R = append(prover.c_R, vec_zero_2...)
-	prover.c_R = append(prover.c_R, inv_sec_key...)
+	prover.c_R = nil
+	prover.c_R = append(prover.c_R, Generate_cons_vec(2+n, big.NewInt(0))...)
+	prover.c_R = append(prover.c_R, utils.Cal_Sub_Vec(prover.b_0, vec_one)...)
+	prover.c_R = append(prover.c_R, Generate_cons_vec(2*k, big.NewInt(0))...)
+	prover.c_R = append(prover.c_R, utils.Cal_Inv_Vec(prover.sec_Vec_Key)...)
 
 	F_rA := utils.Commit(prover.Gen_F, prover.r_A)
 	G_L_H_R := utils.Pedersen_Commit_Vector(Gen_Vec_G0, prover.Gen_Vec_H, prover.c_L, prover.c_R)
-	prover.A.X, prover.A.Y = utils.Curve.Add(F_rA.X, F_rA.Y, G_L_H_R.X, G_L_H_R.Y)
+	prover.A = utils.Cal_Point_Add(F_rA, G_L_H_R)
 
 	//Generate challenge w
-	prover.w = Generate_W(prover.A)
+	fs.Append_Point("A", prover.A)
+	prover.w = fs.Challenge("w")
 
 	//////////////////////////////////////////////Generate commitment B
-	prover.s_L = utils.Generate_Random_Zp_Vector(2+prover.n+prover.N+3*prover.k, prover.d)
-	prover.s_R = utils.Generate_Random_Zp_Vector(2+prover.n+prover.N+3*prover.k, prover.d)
-	prover.r_B = utils.Generate_Random_Zp(prover.d)
+	prover.s_L = prover.nonceVector(len(prover.c_L))
+	prover.s_R = prover.nonceVector(len(prover.c_R))
+	prover.r_B = prover.nonce()
 
 	// Generate G_w
-	Gw := utils.Commit(prover.Gen_G, prover.w)
-	Hw := utils.Commit(prover.Gen_G, prover.w)
-	w_vec := Generate_cons_vec(prover.n, prover.w)
-	var Gen_Vec_Yw []utils.Point
-	for i := 0; i < len(w_vec); i++ {
-		Gen_Vec_Yw = append(Gen_Vec_Yw, utils.Commit(Gen_Vec_Y[i], w_vec[i]))
-	}
-	temp = nil
-	temp = append(temp, Gw)
-	temp = append(temp, Hw)
-	temp = append(temp, Gen_Vec_Yw...)
-	temp = utils.Cal_Point_Add_Vec(temp, prover.Gen_Vec_P)
-	prover.Gen_Vec_Gw = append(temp, prover.Gen_Vec_G...)
+	prover.Gen_Vec_Gw = Generate_Vec_Gw(prover.Gen_G, prover.Gen_H, Gen_Vec_Y, prover.Gen_Vec_P, prover.Gen_Vec_G, prover.w)
 
 	F_rB := utils.Commit(prover.Gen_F, prover.r_B)
 	G_L_H_R = utils.Pedersen_Commit_Vector(prover.Gen_Vec_Gw, prover.Gen_Vec_H, prover.s_L, prover.s_R)
-	prover.B.X, prover.B.Y = utils.Curve.Add(F_rB.X, F_rB.Y, G_L_H_R.X, G_L_H_R.Y)
+	prover.B = utils.Cal_Point_Add(F_rB, G_L_H_R)
 
 	//Compute challenges y,z
-	prover.y, prover.z = Generate_YZ(prover.A, prover.B)
+	fs.Append_Point("B", prover.B)
+	prover.y = fs.Challenge("y")
+	prover.z = fs.Challenge("z")
 }
 
-func (prover *Prover) calculateRound2() {
-	n := prover.n
-	k := prover.k
-	N := prover.N
-	zero_vec := Generate_cons_vec(2+n+N+3*k, big.NewInt(0))
-	var vec_v [9][]*big.Int
-	for i := 0; i < 9; i++ {
-		vec_v[i] = zero_vec
-	}
-	vec_u4 := zero_vec
-
-	////////////////////////////////////////////// Compute constraint vectors
-	vec_yN := Generate_Exp_Scalar_Vector(prover.y, N)
-	copy(vec_v[0][2+n:2+n+N-1], vec_yN)
-	vec_yk := Generate_Exp_Scalar_Vector(prover.y, k)
-	copy(vec_v[1][2+n+N+2*k:2+n+N+3*k-1], vec_yk)
-	// no vec_v2
-	var vec_yk_1n []*big.Int
-	for i := 0; i < k; i++ {
-		vec_yk_1n = append(vec_yk_1n, Generate_cons_vec(n, vec_yk[i])...)
-	}
-	copy(vec_v[3][2+n:2+n+N-1], vec_yk_1n)
-
-	vec_vk := Generate_Exp_Scalar_Vector(prover.v, prover.k)
-	vec_uvk := utils.Cal_Sca_Vec(vec_vk, prover.u)
-	vec_v[4][0] = big.NewInt(1)
-	copy(vec_v[4][2+n+N:2+n+N+k-1], vec_uvk)
-
-	vec_v[5][1] = big.NewInt(1)
-	copy(vec_v[5][2+n+N+k:2+n+N+2*k-1], vec_uvk)
-	copy(vec_v[5][2+n+N+2*k:2+n+N+3*k-1], vec_vk)
-
-	vec_yn := Generate_Exp_Scalar_Vector(prover.y, n)
-	neg_vec_yn := utils.Cal_Neg_Vec(vec_yn)
-	var vec_vk_yn []*big.Int
-	for i := 0; i < k; i++ {
-		vec_vk_yn = append(vec_vk_yn, utils.Cal_Sca_Vec(vec_yn, vec_vk[i])...)
-	}
-	copy(vec_v[6][2:2+n-1], neg_vec_yn)
-	copy(vec_v[6][2+n:2+n+N], vec_vk_yn)
-
-	neg_vec_1k := Generate_cons_vec(k, big.NewInt(-1))
-	copy(vec_v[7][2+n+N:2+n+N+k-1], neg_vec_1k)
-
-	copy(vec_v[8], vec_v[0])
-
-	u2 := utils.Mul_In_P(prover.u, prover.u)
-	vec_u2vk := utils.Cal_Sca_Vec(vec_vk, u2)
-	copy(vec_u4, vec_u2vk)
-
-	vec_theta := zero_vec
-	temp := big.NewInt(1)
-	for i := 0; i < 2; i++ {
-		vec_theta = utils.Cal_Add_Vec(vec_theta, utils.Cal_Sca_Vec(vec_v[i], temp))
-		temp = utils.Mul_In_P(temp, prover.z)
-	}
-
-	vec_ksi := zero_vec
-	temp = utils.Mul_In_P(prover.z, prover.z)
-	for i := 2; i < 8; i++ {
-		vec_ksi = utils.Cal_Add_Vec(vec_ksi, utils.Cal_Sca_Vec(vec_v[i], temp))
-		temp = utils.Mul_In_P(temp, prover.z)
-	}
-
-	vec_mu := zero_vec
-	temp = utils.Mul_In_P(prover.z, prover.z)
-	for i := 2; i < 9; i++ {
-		vec_mu = utils.Cal_Add_Vec(vec_mu, utils.Cal_Sca_Vec(vec_v[i], temp))
-		temp = utils.Mul_In_P(temp, prover.z)
-	}
-
-	temp = big.NewInt(1) // z^8
-	for i := 0; i < 8; i++ {
-		temp = utils.Mul_In_P(temp, prover.z)
-	}
-	vec_vv := utils.Cal_Sca_Vec(vec_v[8], temp)
-
-	temp = big.NewInt(1) // z^4
-	for i := 0; i < 4; i++ {
-		temp = utils.Mul_In_P(temp, prover.z)
-	}
-	vec_ww := utils.Cal_Sca_Vec(vec_u4, temp)
-
-	prover.vec_inv_theta = utils.Cal_Inv_Vec(vec_theta)
-	vec_ww_vv := utils.Cal_Sub_Vec(vec_ww, vec_vv)
-	vec_alpha := utils.Cal_HP_Vec(prover.vec_inv_theta, vec_ww_vv)
-	// TODO
-	// vec_beta, _ := utils.Cal_HP_Vec(vec_inv_theta, vec_mu) // Calculated by the verifier, as well as theta and mu
-
-	// Compute T_1, T_2
-	// Compute t_1
-	vec_cL_alpha := utils.Cal_Add_Vec(prover.c_L, vec_alpha)
-	vec_theta_sR := utils.Cal_HP_Vec(vec_theta, prover.s_R)
-	t1L := utils.Cal_IP_Vec(vec_cL_alpha, vec_theta_sR)
-	vec_theta_cR := utils.Cal_HP_Vec(vec_theta, prover.c_R)
-	vec_theta_cR_mu := utils.Cal_Add_Vec(vec_theta_cR, vec_mu)
-	t1R := utils.Cal_IP_Vec(prover.s_L, vec_theta_cR_mu)
-	t_1 := utils.Add_In_P(t1L, t1R)
-
-	//Generate tau2
-	prover.tau_1 = utils.Generate_Random_Zp(prover.d)
-
-	//Compute T1
-	prover.T1 = utils.Pedersen_Commit(prover.Gen_G, prover.Gen_F, t_1, prover.tau_1)
-
-	//Compute t_2
-	vec_theta_s_R := utils.Cal_HP_Vec(vec_theta, prover.s_R)
-	t_2 := utils.Cal_IP_Vec(prover.s_L, vec_theta_s_R)
+//Generate Commitments T1, T2 and the response
+func (prover *Prover) calculateRound2(fs *utils.Fiat_Shamir) {
+	vec_theta, vec_alpha, vec_mu, _ := Generate_Constraints(prover.u, prover.v, prover.y, prover.z, prover.k, prover.n, prover.N)
 
-	//Generate tau2
-	prover.tau_2 = utils.Generate_Random_Zp(prover.d)
+	// l(x) = c_L + alpha + s_L x, r(x) = theta \circ (c_R + s_R x) + mu
+	l_0 := utils.Cal_Add_Vec(prover.c_L, vec_alpha)
+	r_0 := utils.Cal_Add_Vec(utils.Cal_HP_Vec(vec_theta, prover.c_R), vec_mu)
+	r_1 := utils.Cal_HP_Vec(vec_theta, prover.s_R)
 
-	//Compute T2
-	prover.T2 = utils.Pedersen_Commit(prover.Gen_G, prover.Gen_F, t_2, prover.tau_2)
+	//Compute T1, T2 with t_1 = <l_0, r_1> + <s_L, r_0>, t_2 = <s_L, r_1>
+	t_1 := utils.Add_In_P(utils.Cal_IP_Vec(l_0, r_1), utils.Cal_IP_Vec(prover.s_L, r_0))
+	t_2 := utils.Cal_IP_Vec(prover.s_L, r_1)
+	prover.tau_1 = prover.nonce()
+	prover.tau_2 = prover.nonce()
+	prover.T1 = utils.Pedersen_Commit(prover.Gen_G, prover.Gen_H, t_1, prover.tau_1)
+	prover.T2 = utils.Pedersen_Commit(prover.Gen_G, prover.Gen_H, t_2, prover.tau_2)
 
-	//prover compute Commitments T1, T2
 	// Compute x
-	prover.x = Generate_X(prover.T1, prover.T2)
-	////////////////////////////////////////////// Calculate rx, i.e., zeta
-	vec_sL_x := utils.Cal_Sca_Vec(prover.s_L, prover.x)
-	prover.zeta = utils.Cal_Add_Vec(vec_cL_alpha, vec_sL_x)
-
-	////////////////////////////////////////////// Calculate lx, i.e., eta
-	vec_sR_x := utils.Cal_Sca_Vec(prover.s_R, prover.x)
-	vec_cR_sRx := utils.Cal_Add_Vec(prover.c_R, vec_sR_x)
-	vec_theta_cR_sRx := utils.Cal_HP_Vec(vec_theta, vec_cR_sRx)
-	prover.eta = utils.Cal_Add_Vec(vec_theta_cR_sRx, vec_mu)
-
-	//Compute t = <l(x), r(x)>
-	prover.ip = utils.Cal_IP_Vec(prover.eta, prover.zeta)
-
-	//Compute tau_x = tau_1 x + tau_2 x^2
-	z2 := utils.Mul_In_P(prover.z, prover.z)
-	vec_yk2 := Generate_Exp_Scalar_Vector(prover.y, len(prover.out_Vec_Value))
-	tau0 := utils.Mul_In_P(z2, utils.Cal_IP_Vec(prover.out_Vec_Random, vec_yk2))
-
+	fs.Append_Point("T1", prover.T1)
+	fs.Append_Point("T2", prover.T2)
+	prover.x = fs.Challenge("x")
+
+	//Compute zeta = l(x), eta = r(x) and t = <zeta, eta>
+	prover.zeta = utils.Cal_Add_Vec(l_0, utils.Cal_Sca_Vec(prover.s_L, prover.x))
+	prover.eta = utils.Cal_Add_Vec(r_0, utils.Cal_Sca_Vec(r_1, prover.x))
+	prover.ip = utils.Cal_IP_Vec(prover.zeta, prover.eta)
+
+	//Compute tau_x = tau_1 x + tau_2 x^2 - z^7 <1, r_out>
+	z7 := big.NewInt(1)
+	for i := 0; i < 7; i++ {
+		z7 = utils.Mul_In_P(z7, prover.z)
+	}
+	vec_1 := Generate_cons_vec(len(prover.out_Vec_Random), big.NewInt(1))
+	tau0 := utils.Neg_Zp(utils.Mul_In_P(z7, utils.Cal_IP_Vec(prover.out_Vec_Random, vec_1)))
 	tau1_x := utils.Mul_In_P(prover.tau_1, prover.x)
 	tau2_x2 := utils.Mul_In_P(prover.tau_2, utils.Mul_In_P(prover.x, prover.x))
 	prover.tau_x = utils.Add_In_P(tau0, tau1_x)
@@ -400,4 +286,7 @@ func (prover *Prover) calculateRound2() {
 
 	//Compute mu
 	prover.mu = utils.Add_In_P(prover.r_A, utils.Mul_In_P(prover.r_B, prover.x))
+
+	//The openings eta are taken over h^{theta^{-1}}
+	prover.Gen_Vec_Hy = utils.Generate_Point_Vector_with_y(prover.Gen_Vec_H, utils.Cal_Inv_Vec(vec_theta))
 }
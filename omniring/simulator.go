@@ -0,0 +1,132 @@
+package omniring
+
+import (
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Verifier challenges of an interactive omniring proof
+type Challenges struct {
+	W, Y, Z, X *big.Int
+}
+
+// Simulate an interactive transcript for the public keys and coins set in the verifier and the given challenges,
+// without any witness. The openings zeta, eta, the responses tau_x, mu and the commitments B, T2 are uniform,
+// A and T1 are the unique values that satisfy the two verification equations
+func (verifier *Verifier) Simulate(challenges Challenges) Transcript {
+	size := 2 + verifier.n + verifier.N + 3*verifier.k
+	trans := Transcript{
+		B:     utils.Commit(verifier.Gen_F, randomScalar()),
+		T2:    utils.Commit(verifier.Gen_F, randomScalar()),
+		Tau_x: randomScalar(),
+		Mu:    randomScalar(),
+		Zeta:  randomVector(size),
+		Eta:   randomVector(size),
+		W:     challenges.W,
+		X:     challenges.X,
+		Y:     challenges.Y,
+		Z:     challenges.Z,
+	}
+	trans.Ip = utils.Cal_IP_Vec(trans.Zeta, trans.Eta)
+	verifier.load(trans)
+	vec_alpha, vec_mu, delta := verifier.prepare()
+
+	// A = G_w^{zeta - alpha} H^{eta - mu} F^{mu} B^{-x}
+	var me utils.Multi_Exp
+	me.Add_Terms(verifier.Gen_Vec_Gw, utils.Cal_Sub_Vec(trans.Zeta, vec_alpha))
+	me.Add_Terms(verifier.Gen_Vec_Hy, utils.Cal_Sub_Vec(trans.Eta, vec_mu))
+	me.Add_Term(verifier.Gen_F, trans.Mu)
+	me.Add_Term(trans.B, utils.Neg_Zp(trans.X))
+	trans.A = me.Calculate()
+
+	// T1 = (G^{ip - delta} H^{tau_x} Coin^{z^7} T2^{-x^2})^{1/x}
+	inv_x := utils.Inverse_Zp(trans.X)
+	z7 := big.NewInt(1)
+	for i := 0; i < 7; i++ {
+		z7 = utils.Mul_In_P(z7, trans.Z)
+	}
+	var me_T utils.Multi_Exp
+	me_T.Add_Term(verifier.Gen_G, utils.Mul_In_P(inv_x, utils.Sub_In_P(trans.Ip, delta)))
+	me_T.Add_Term(verifier.Gen_H, utils.Mul_In_P(inv_x, trans.Tau_x))
+	me_T.Add_Terms(verifier.Out_Vec_Coin, Generate_cons_vec(len(verifier.Out_Vec_Coin), utils.Mul_In_P(inv_x, z7)))
+	me_T.Add_Term(trans.T2, utils.Neg_Zp(trans.X))
+	trans.T1 = me_T.Calculate()
+	return trans
+}
+
+// Check a well-formed interactive transcript with uncompressed openings against the public keys and coins set in the verifier:
+// G_w^{zeta} H^{eta} = F^{-mu} A B^x G_w^{alpha} H^{mu} and G^{ip} H^{tau_x} = G^{delta} Coin^{-z^7} T1^x T2^{x^2}
+// with H = h^{theta^{-1}} and ip = <zeta, eta>
+func (verifier *Verifier) CheckTranscript(trans Transcript) bool {
+	size := 2 + verifier.n + verifier.N + 3*verifier.k
+	if len(trans.Zeta) != size || len(trans.Eta) != size || len(verifier.Gen_Vec_H) != size || len(trans.L) != 0 || len(trans.R) != 0 {
+		return false
+	}
+	if len(verifier.Pub_Vec_Key) != verifier.n || len(verifier.Inp_Vec_Coin) != verifier.n {
+		return false
+	}
+	for _, point := range []utils.Point{trans.A, trans.B, trans.T1, trans.T2} {
+		if !utils.Is_Valid_Point(point) {
+			return false
+		}
+	}
+	for _, c := range []*big.Int{trans.W, trans.X, trans.Y, trans.Z} {
+		if !utils.Is_Valid_Scalar(c) || c.Sign() == 0 {
+			return false
+		}
+	}
+	scalars := append([]*big.Int{trans.Tau_x, trans.Mu, trans.Ip}, trans.Zeta...)
+	for _, scalar := range append(scalars, trans.Eta...) {
+		if !utils.Is_Valid_Scalar(scalar) {
+			return false
+		}
+	}
+	if utils.Cal_IP_Vec(trans.Zeta, trans.Eta).Cmp(trans.Ip) != 0 {
+		return false
+	}
+	LHS, RHS := verifier.transcriptSides(trans)
+	return utils.Is_Equal_Point(LHS, RHS)
+}
+
+////////////////////////Private functions
+
+// Load the transcript into the verifier
+func (verifier *Verifier) load(trans Transcript) {
+	verifier.Trans = trans
+	verifier.A = trans.A
+	verifier.B = trans.B
+	verifier.T1 = trans.T1
+	verifier.T2 = trans.T2
+	verifier.tau_x = trans.Tau_x
+	verifier.mu = trans.Mu
+	verifier.ip = trans.Ip
+	verifier.w = trans.W
+	verifier.x = trans.X
+	verifier.y = trans.Y
+	verifier.z = trans.Z
+}
+
+// Load the transcript into the verifier and evaluate both sides of the combined verification equation
+func (verifier *Verifier) transcriptSides(trans Transcript) (utils.Point, utils.Point) {
+	verifier.load(trans)
+	RHS, Gen_Vec_Gw, Gen_Vec_Hy := verifier.Validate()
+
+	points := append(append(append([]utils.Point{}, Gen_Vec_Gw...), Gen_Vec_Hy...), verifier.Gen_G)
+	scalars := append(append(append([]*big.Int{}, trans.Zeta...), trans.Eta...), trans.Ip)
+	return utils.Multi_Scalar_Mult(points, scalars), RHS
+}
+
+// Draw a uniform element of Zp
+func randomScalar() *big.Int {
+	return utils.Add_In_P(utils.Generate_Random_Zp(q), big.NewInt(0))
+}
+
+// Draw n uniform elements of Zp
+func randomVector(n int) []*big.Int {
+	var res []*big.Int
+	for i := 0; i < n; i++ {
+		res = append(res, randomScalar())
+	}
+	return res
+}
@@ -1,7 +1,6 @@
 package omniring
 
 import (
-	"math"
 	"math/big"
 
 	"anyOutOfMany/utils"
@@ -12,7 +11,8 @@ type Verifier struct {
 	u, v                            *big.Int
 	Gen_F, Gen_G, Gen_H             utils.Point
 	Gen_Vec_G, Gen_Vec_H, Gen_Vec_P []utils.Point
-	Gen_Vec_Gw                      []utils.Point // public key vector i.e., ring set
+	Gen_Vec_Gw                      []utils.Point // generator vector G_w of the openings zeta
+	Gen_Vec_Hy                      []utils.Point // generator vector h^{theta^{-1}} of the openings eta
 	Pub_Vec_Key                     []utils.Point
 	Out_Vec_Coin, Inp_Vec_Coin      []utils.Point
 	A, B                            utils.Point // commitments A, B
@@ -66,185 +66,80 @@ func (verifier *Verifier) ParseZKP() (utils.Point, []utils.Point, []utils.Point)
 	verifier.ip = verifier.Trans.Ip
 	verifier.L = verifier.Trans.L
 	verifier.R = verifier.Trans.R
-	verifier.w = verifier.Trans.X
-	verifier.x = verifier.Trans.X
-	verifier.y = verifier.Trans.Y
-	verifier.z = verifier.Trans.Z
 
-	// Check the challenges
-	temp_w := Generate_W(verifier.A)
-	temp_y, temp_z := Generate_YZ(verifier.A, verifier.B)
-	temp_x := Generate_X(verifier.T1, verifier.T2)
-
-	var bit0 bool
-	if temp_w.Cmp(verifier.w) != 0 || temp_x.Cmp(verifier.x) != 0 || temp_y.Cmp(verifier.y) != 0 || temp_z.Cmp(verifier.z) != 0 {
-		bit0 = false
-	}
-	_ = bit0
+	// Recompute the challenges
+	verifier.w, verifier.y, verifier.z, verifier.x = verifier.challenges(verifier.Trans)
 
 	RHS, Gen_Vec_G, Gen_Vec_H := verifier.Validate()
 	return RHS, Gen_Vec_G, Gen_Vec_H
 }
 
-func (verifier *Verifier) Validate() (utils.Point, []utils.Point, []utils.Point) {
-	// Parameters for Left hand side
-	// Generate commitment Y
-	Coin_u := utils.Cal_Point_Sca_Vec(verifier.Inp_Vec_Coin, verifier.u) // Coin^u
-	Gen_Vec_Y := utils.Cal_Point_Add_Vec(verifier.Pub_Vec_Key, Coin_u)   // Y = Pk \circ Coin^u
-
-	// Generate G_w
-	Gw := utils.Commit(verifier.Gen_G, verifier.w)
-	Hw := utils.Commit(verifier.Gen_G, verifier.w)
-	w_vec := Generate_cons_vec(verifier.n, verifier.w)
-	var Gen_Vec_Yw []utils.Point
-	for i := 0; i < len(w_vec); i++ {
-		Gen_Vec_Yw = append(Gen_Vec_Yw, utils.Commit(Gen_Vec_Y[i], w_vec[i]))
-	}
-	var temp_point []utils.Point
-	temp_point = append(temp_point, Gw)
-	temp_point = append(temp_point, Hw)
-	temp_point = append(temp_point, Gen_Vec_Yw...)
-	temp_point = utils.Cal_Point_Add_Vec(temp_point, verifier.Gen_Vec_P)
-	verifier.Gen_Vec_Gw = append(temp_point, verifier.Gen_Vec_G...)
-
-	////////////////////////////////////////////// Compute constraint vectors
-	n := verifier.n
-	k := verifier.k
-	N := verifier.N
-	zero_vec := Generate_cons_vec(2+n+N+3*k, big.NewInt(0))
-	var vec_v [9][]*big.Int
-	for i := 0; i < 9; i++ {
-		vec_v[i] = zero_vec
-	}
-	vec_u4 := zero_vec
-
-	vec_yN := Generate_Exp_Scalar_Vector(verifier.y, N)
-	copy(vec_v[0][2+n:2+n+N-1], vec_yN)
-	vec_yk := Generate_Exp_Scalar_Vector(verifier.y, k)
-	copy(vec_v[1][2+n+N+2*k:2+n+N+3*k-1], vec_yk)
-	// no vec_v2
-	var vec_yk_1n []*big.Int
-	for i := 0; i < k; i++ {
-		vec_yk_1n = append(vec_yk_1n, Generate_cons_vec(n, vec_yk[i])...)
-	}
-	copy(vec_v[3][2+n:2+n+N-1], vec_yk_1n)
-
-	vec_vk := Generate_Exp_Scalar_Vector(verifier.v, verifier.k)
-	vec_uvk := utils.Cal_Sca_Vec(vec_vk, verifier.u)
-	vec_v[4][0] = big.NewInt(1)
-	copy(vec_v[4][2+n+N:2+n+N+k-1], vec_uvk)
-
-	vec_v[5][1] = big.NewInt(1)
-	copy(vec_v[5][2+n+N+k:2+n+N+2*k-1], vec_uvk)
-	copy(vec_v[5][2+n+N+2*k:2+n+N+3*k-1], vec_vk)
-
-	vec_yn := Generate_Exp_Scalar_Vector(verifier.y, n)
-	neg_vec_yn := utils.Cal_Neg_Vec(vec_yn)
-	var vec_vk_yn []*big.Int
-	for i := 0; i < k; i++ {
-		vec_vk_yn = append(vec_vk_yn, utils.Cal_Sca_Vec(vec_yn, vec_vk[i])...)
-	}
-	copy(vec_v[6][2:2+n-1], neg_vec_yn)
-	copy(vec_v[6][2+n:2+n+N], vec_vk_yn)
-
-	neg_vec_1k := Generate_cons_vec(k, big.NewInt(-1))
-	copy(vec_v[7][2+n+N:2+n+N+k-1], neg_vec_1k)
-
-	copy(vec_v[8], vec_v[0])
-
-	u2 := utils.Mul_In_P(verifier.u, verifier.u)
-	vec_u2vk := utils.Cal_Sca_Vec(vec_vk, u2)
-	copy(vec_u4, vec_u2vk)
-
-	vec_theta := zero_vec
-	temp := big.NewInt(1)
-	for i := 0; i < 2; i++ {
-		vec_theta = utils.Cal_Add_Vec(vec_theta, utils.Cal_Sca_Vec(vec_v[i], temp))
-		temp = utils.Mul_In_P(temp, verifier.z)
-	}
-
-	vec_ksi := zero_vec
-	temp = utils.Mul_In_P(verifier.z, verifier.z)
-	for i := 2; i < 8; i++ {
-		vec_ksi = utils.Cal_Add_Vec(vec_ksi, utils.Cal_Sca_Vec(vec_v[i], temp))
-		temp = utils.Mul_In_P(temp, verifier.z)
-	}
-
-	vec_mu := zero_vec
-	temp = utils.Mul_In_P(verifier.z, verifier.z)
-	for i := 2; i < 9; i++ {
-		vec_mu = utils.Cal_Add_Vec(vec_mu, utils.Cal_Sca_Vec(vec_v[i], temp))
-		temp = utils.Mul_In_P(temp, verifier.z)
-	}
-
-	temp = big.NewInt(1) // z^8
-	for i := 0; i < 8; i++ {
-		temp = utils.Mul_In_P(temp, verifier.z)
+// Check a transcript with uncompressed openings whose challenges are derived with Fiat-Shamir
+func (verifier *Verifier) Verify(trans Transcript) bool {
+	w, y, z, x := verifier.challenges(trans)
+	for i, c := range []*big.Int{trans.W, trans.Y, trans.Z, trans.X} {
+		if c == nil || c.Cmp([]*big.Int{w, y, z, x}[i]) != 0 {
+			return false
+		}
 	}
-	vec_vv := utils.Cal_Sca_Vec(vec_v[8], temp)
+	return verifier.CheckTranscript(trans)
+}
 
-	temp = big.NewInt(1) // z^4
-	for i := 0; i < 4; i++ {
-		temp = utils.Mul_In_P(temp, verifier.z)
+// Compute the commitment G_w^{zeta} H^{eta} G^{ip} of the openings, with H = h^{theta^{-1}}, as
+// F^{-mu} A B^x G_w^{alpha} H^{mu} G^{ip} (G^{delta - ip} Coin^{-z^7} T1^x T2^{x^2} H^{-tau_x})^rho
+// for a random rho, so that it is only correct if both sides of the two verification equations agree
+func (verifier *Verifier) Validate() (utils.Point, []utils.Point, []utils.Point) {
+	vec_alpha, vec_mu, delta := verifier.prepare()
+	rho := utils.Generate_Random_Zp(q)
+	x2 := utils.Mul_In_P(verifier.x, verifier.x)
+	z7 := big.NewInt(1)
+	for i := 0; i < 7; i++ {
+		z7 = utils.Mul_In_P(z7, verifier.z)
 	}
-	vec_ww := utils.Cal_Sca_Vec(vec_u4, temp)
-
-	vec_inv_theta := utils.Cal_Inv_Vec(vec_theta)
-	vec_ww_vv := utils.Cal_Sub_Vec(vec_ww, vec_vv)
-	vec_alpha := utils.Cal_HP_Vec(vec_inv_theta, vec_ww_vv)
-	vec_beta := utils.Cal_HP_Vec(vec_inv_theta, vec_mu) // Calculated by the verifier, as well as theta and mu
 
-	// Compute Right hand side
-	var RHS utils.Point
+	var me utils.Multi_Exp
 
 	// Compute the part in Step (1)
-	F_neg_mu := utils.Commit(verifier.Gen_F, utils.Neg_Zp(verifier.mu))
-	A_S_x := utils.Cal_Point_Add(verifier.A, utils.Commit(verifier.B, verifier.x))
-	Gw_H := utils.Pedersen_Commit_Vector(verifier.Gen_Vec_Gw, verifier.Gen_Vec_H, vec_alpha, vec_beta)
-	RHS = utils.Cal_Point_Add(F_neg_mu, A_S_x)
-	RHS = utils.Cal_Point_Add(RHS, Gw_H)
-
-	// Compute delta
-	vec_1k := Generate_Exp_Scalar_Vector(big.NewInt(1), k)
-	delta_1 := utils.Mul_In_P(verifier.z, utils.Mul_In_P(verifier.z, utils.Cal_IP_Vec(vec_1k, vec_yk)))
-	vec_1k_1 := append(vec_1k, big.NewInt(1))
-	vec_yk_1 := Generate_Exp_Scalar_Vector(verifier.y, k+1)
-	z2 := utils.Mul_In_P(verifier.z, verifier.z)
-	z3 := utils.Mul_In_P(z2, verifier.z)
-	delta_2 := utils.Mul_In_P(z3, utils.Cal_IP_Vec(vec_1k_1, vec_yk_1))
-	delta_3 := utils.Cal_IP_Vec(vec_alpha, vec_mu)
-	vec_1kN := Generate_Exp_Scalar_Vector(big.NewInt(1), len(verifier.Gen_Vec_Gw))
-	delta_4 := utils.Cal_IP_Vec(vec_1kN, vec_vv)
-	delta12 := utils.Add_In_P(delta_1, delta_2)
-	delta34 := utils.Add_In_P(delta_3, delta_4)
-	delta := utils.Add_In_P(delta12, delta34)
-	G_delta := utils.Commit(verifier.Gen_G, delta)
-	RHS = utils.Cal_Point_Add(RHS, G_delta)
-
-	vec_yk2 := Generate_Exp_Scalar_Vector(verifier.y, len(verifier.Out_Vec_Coin))
-	vec_z2_yk2 := utils.Cal_Sca_Vec(vec_yk2, z2)
-	Coin_y := utils.Commit_Vector(verifier.Out_Vec_Coin, vec_z2_yk2)
-
-	RHS = utils.Cal_Point_Add(RHS, Coin_y)
-
-	x2 := utils.Mul_In_P(verifier.x, verifier.x)
-	T1_T2 := utils.Pedersen_Commit(verifier.T1, verifier.T2, verifier.x, x2)
-	RHS = utils.Cal_Point_Add(RHS, T1_T2)
-
-	// Padding the vectors to 2^n length
-	deg := math.Ceil(math.Log2(float64(len(verifier.Gen_Vec_Gw))))
-	pad := math.Pow(2, deg) - float64(len(verifier.Gen_Vec_Gw))
-	zero_vec_pad := Generate_cons_vec(int(pad), big.NewInt(0))
-	var one_vec_pad []utils.Point
-	for i := 0; i < len(zero_vec_pad); i++ {
-		one_vec_pad = append(one_vec_pad, utils.Commit(verifier.Gen_F, big.NewInt(0)))
-	}
-	verifier.Gen_Vec_Gw = append(verifier.Gen_Vec_Gw, one_vec_pad...)
-	verifier.Gen_Vec_H = append(verifier.Gen_Vec_H, one_vec_pad...)
+	me.Add_Term(verifier.Gen_F, utils.Neg_Zp(verifier.mu))
+	me.Add_Term(verifier.A, big.NewInt(1))
+	me.Add_Term(verifier.B, verifier.x)
+	me.Add_Terms(verifier.Gen_Vec_Gw, vec_alpha)
+	me.Add_Terms(verifier.Gen_Vec_Hy, vec_mu)
+
+	// Compute the part in Step (2), i.e., the polynomial commitment to ip
+	ip_delta := utils.Sub_In_P(delta, verifier.ip)
+	me.Add_Term(verifier.Gen_G, utils.Add_In_P(verifier.ip, utils.Mul_In_P(rho, ip_delta)))
+	me.Add_Terms(verifier.Out_Vec_Coin, Generate_cons_vec(len(verifier.Out_Vec_Coin), utils.Neg_Zp(utils.Mul_In_P(rho, z7))))
+	me.Add_Term(verifier.T1, utils.Mul_In_P(rho, verifier.x))
+	me.Add_Term(verifier.T2, utils.Mul_In_P(rho, x2))
+	me.Add_Term(verifier.Gen_H, utils.Neg_Zp(utils.Mul_In_P(rho, verifier.tau_x)))
+
+	return me.Calculate(), verifier.Gen_Vec_Gw, verifier.Gen_Vec_Hy
+}
 
-	// bool := opt_verify(verifier.L, verifier.R, RHS, verifier.Gen_Vec_Gw, verifier.Gen_Vec_H, verifier.Gen_G, verifier.c_zeta, verifier.c_eta)
+////////////////////////Private functions
+
+// Derive the challenges w, y, z, x of a transcript
+func (verifier *Verifier) challenges(trans Transcript) (*big.Int, *big.Int, *big.Int, *big.Int) {
+	fs := newTranscript(verifier.u, verifier.v, verifier.Gen_F, verifier.Gen_G, verifier.Gen_H, verifier.Gen_Vec_P, verifier.Gen_Vec_G, verifier.Gen_Vec_H, verifier.Pub_Vec_Key, verifier.Inp_Vec_Coin, verifier.Out_Vec_Coin, verifier.k, verifier.N)
+	fs.Append_Point("A", trans.A)
+	w := fs.Challenge("w")
+	fs.Append_Point("B", trans.B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+	fs.Append_Point("T1", trans.T1)
+	fs.Append_Point("T2", trans.T2)
+	x := fs.Challenge("x")
+	return w, y, z, x
+}
 
-	return RHS, verifier.Gen_Vec_Gw, verifier.Gen_Vec_H
+// Compute the generator vectors G_w, h^{theta^{-1}} and the constraints alpha, mu, delta for the current challenges
+func (verifier *Verifier) prepare() ([]*big.Int, []*big.Int, *big.Int) {
+	Gen_Vec_Y := Generate_Vec_Y(verifier.Pub_Vec_Key, verifier.Inp_Vec_Coin, verifier.u)
+	verifier.Gen_Vec_Gw = Generate_Vec_Gw(verifier.Gen_G, verifier.Gen_H, Gen_Vec_Y, verifier.Gen_Vec_P, verifier.Gen_Vec_G, verifier.w)
+	vec_theta, vec_alpha, vec_mu, delta := Generate_Constraints(verifier.u, verifier.v, verifier.y, verifier.z, verifier.k, verifier.n, verifier.N)
+	verifier.Gen_Vec_Hy = utils.Generate_Point_Vector_with_y(verifier.Gen_Vec_H, utils.Cal_Inv_Vec(vec_theta))
+	return vec_alpha, vec_mu, delta
 }
 
 // func (verifier *Verifier) checkSk() bool {
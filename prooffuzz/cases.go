@@ -0,0 +1,158 @@
+package main
+
+import (
+	"math/big"
+
+	"anyOutOfMany/any_proofs"
+	"anyOutOfMany/omniring"
+	"anyOutOfMany/range_proofs"
+	"anyOutOfMany/utils"
+)
+
+// Protocol under test: a statement with a valid proof, encoded in the corpus
+type Case interface {
+	Valid() interface{}            // pointer to the valid proof
+	NewProof() interface{}         // pointer to an empty proof of the same type
+	Verify(proof interface{}) bool // whether the verifier accepts the proof
+}
+
+// Protocols by name, each with a generator of a fresh case and an empty case to decode the corpus into
+var protocols = []struct {
+	name     string
+	generate func() (Case, error)
+	empty    func() Case
+}{
+	{"any_proofs", newAnyCase, func() Case { return &anyCase{} }},
+	{"range_proofs", newRangeCase, func() Case { return &rangeCase{} }},
+	{"omniring", newOmniringCase, func() Case { return &omniringCase{} }},
+	{"inner_product", newInnerProductCase, func() Case { return &innerProductCase{} }},
+}
+
+// Any-out-of-many proof with 2 secrets in a ring of 4
+type anyCase struct {
+	Statement any_proofs.Statement
+	Proof     any_proofs.Proof
+}
+
+func newAnyCase() (Case, error) {
+	var prover any_proofs.Prover
+	prover.New(utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint(), utils.GenerateMultiPoint(4), utils.GenerateMultiPoint(4), 2, 4)
+	statement, proof := prover.GenerateProof()
+	return &anyCase{Statement: statement, Proof: proof}, nil
+}
+
+func (c *anyCase) Valid() interface{}    { return &c.Proof }
+func (c *anyCase) NewProof() interface{} { return &any_proofs.Proof{} }
+func (c *anyCase) Verify(proof interface{}) bool {
+	return any_proofs.Verify(c.Statement, *proof.(*any_proofs.Proof)) == nil
+}
+
+// Aggregated range proof of two 8-bit values
+type rangeCase struct {
+	Statement range_proofs.Statement
+	Proof     range_proofs.Proof
+}
+
+func newRangeCase() (Case, error) {
+	var params range_proofs.Params
+	params.New(utils.GeneratePoint(), utils.GeneratePoint(), utils.GenerateMultiPoint(16), utils.GenerateMultiPoint(16))
+	blindings := []*big.Int{utils.Add_In_P(utils.Generate_Random_Zp(256), big.NewInt(0)), utils.Add_In_P(utils.Generate_Random_Zp(256), big.NewInt(0))}
+	statement, proof, err := params.ProveMultiple([]uint64{42, 255}, blindings, 8)
+	if err != nil {
+		return nil, err
+	}
+	return &rangeCase{Statement: statement, Proof: proof}, nil
+}
+
+func (c *rangeCase) Valid() interface{}    { return &c.Proof }
+func (c *rangeCase) NewProof() interface{} { return &range_proofs.Proof{} }
+func (c *rangeCase) Verify(proof interface{}) bool {
+	return range_proofs.Verify(c.Statement, *proof.(*range_proofs.Proof)) == nil
+}
+
+// Omniring transcript of a real prover with 1 secret among 4 source accounts
+type omniringCase struct {
+	U, V                         *big.Int
+	F, G, H                      utils.Point
+	P_Vector, G_Vector, H_Vector []utils.Point
+	K, N, D                      int
+	Keys, Inputs, Outputs        []utils.Point
+	Proof                        omniring.Transcript
+}
+
+func newOmniringCase() (Case, error) {
+	c := &omniringCase{K: 1, N: 4, D: 16}
+	n := c.N / c.K
+	c.U, c.V = utils.Generate_Random_Zp(c.D), utils.Generate_Random_Zp(c.D)
+	c.F, c.G, c.H = utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint()
+	c.P_Vector = utils.GenerateMultiPoint(2 + c.N)
+	c.G_Vector = utils.GenerateMultiPoint(3*c.K + c.N)
+	c.H_Vector = utils.GenerateMultiPoint(2 + n + 3*c.K + c.N)
+	var prover omniring.Prover
+	prover.New(c.U, c.V, c.F, c.G, c.H, c.P_Vector, c.G_Vector, c.H_Vector, c.K, c.N, c.D)
+	c.Keys, c.Inputs, c.Outputs, c.Proof, _, _ = prover.GenerateRsp()
+	return c, nil
+}
+
+func (c *omniringCase) Valid() interface{}    { return &c.Proof }
+func (c *omniringCase) NewProof() interface{} { return &omniring.Transcript{} }
+func (c *omniringCase) Verify(proof interface{}) bool {
+	verifier := c.verifier()
+	return verifier.Verify(*proof.(*omniring.Transcript))
+}
+
+func (c *omniringCase) verifier() *omniring.Verifier {
+	var verifier omniring.Verifier
+	verifier.New(c.U, c.V, c.F, c.G, c.H, c.P_Vector, c.G_Vector, c.H_Vector, c.K, c.N, c.D)
+	verifier.Pub_Vec_Key = c.Keys
+	verifier.Inp_Vec_Coin = c.Inputs
+	verifier.Out_Vec_Coin = c.Outputs
+	return &verifier
+}
+
+// Inner-product argument of length 6 for a commitment P = g^a h^b with <a, b> = C, whose forgeries use
+// challenges independent of the transcript
+type innerProductCase struct {
+	G_Vector, H_Vector []utils.Point
+	V, P               utils.Point
+	C                  *big.Int
+	Proof              innerProductProof
+}
+
+type innerProductProof struct {
+	L, R []utils.Point
+	A, B []*big.Int // compressed a, b
+}
+
+func newInnerProductCase() (Case, error) {
+	N := 6
+	c := &innerProductCase{G_Vector: utils.GenerateMultiPoint(N), H_Vector: utils.GenerateMultiPoint(N), V: utils.GeneratePoint()}
+	a := utils.Generate_Random_Zp_Vector(N, 256)
+	b := utils.Generate_Random_Zp_Vector(N, 256)
+	c.P = utils.Pedersen_Commit_Vector(c.G_Vector, c.H_Vector, a, b)
+	c.C = utils.Cal_IP_Vec(a, b)
+	c.Proof.L, c.Proof.R, c.Proof.A, c.Proof.B = utils.Opt_Prove(c.transcript(), a, b, c.G_Vector, c.H_Vector, c.V)
+	return c, nil
+}
+
+func (c *innerProductCase) Valid() interface{}    { return &c.Proof }
+func (c *innerProductCase) NewProof() interface{} { return &innerProductProof{} }
+func (c *innerProductCase) Verify(proof interface{}) bool {
+	p := proof.(*innerProductProof)
+	return utils.Opt_Verify(c.transcript(), p.L, p.R, c.P, c.C, c.G_Vector, c.H_Vector, c.V, p.A, p.B)
+}
+
+// Arguments for the same P and C forged by fixing L_j + R_j before the round challenges
+func (c *innerProductCase) Forgeries() ([]Mutant, error) {
+	return forgedMutants(3, func() (interface{}, error) {
+		L, R, a, b, err := forgeInnerProduct(c.G_Vector, c.H_Vector, c.V, c.P, c.C)
+		return &innerProductProof{L: L, R: R, A: []*big.Int{a}, B: []*big.Int{b}}, err
+	})
+}
+
+func (c *innerProductCase) transcript() *utils.Fiat_Shamir {
+	var fs utils.Fiat_Shamir
+	fs.New("prooffuzz/inner_product")
+	fs.Append_Point("P", c.P)
+	return &fs
+}
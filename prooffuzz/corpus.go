@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Regression corpus of one protocol: a statement with its valid proof and the mutants that must be rejected
+type Corpus struct {
+	Protocol string
+	Case     json.RawMessage
+	Mutants  []Mutant
+}
+
+// Result of checking a corpus
+type Result struct {
+	Valid    bool     // the valid proof is accepted
+	Mutants  int      // number of mutants checked
+	Accepted []string // mutants accepted by the verifier
+	Panicked []string // mutants on which the verifier panicked
+}
+
+// Build the corpus of a case
+func NewCorpus(protocol string, c Case) (Corpus, error) {
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		return Corpus{}, err
+	}
+	mutants, err := Mutants(c.Valid())
+	if err != nil {
+		return Corpus{}, err
+	}
+	if forger, ok := c.(Forger); ok {
+		forged, err := forger.Forgeries()
+		if err != nil {
+			return Corpus{}, err
+		}
+		mutants = append(mutants, forged...)
+	}
+	return Corpus{Protocol: protocol, Case: encoded, Mutants: mutants}, nil
+}
+
+// Verify the valid proof and every mutant of the corpus
+func (corpus *Corpus) Check(c Case) (Result, error) {
+	if err := json.Unmarshal(corpus.Case, c); err != nil {
+		return Result{}, err
+	}
+	var result Result
+	result.Valid, _ = verify(c, c.Valid())
+	for _, mutant := range corpus.Mutants {
+		proof := c.NewProof()
+		if err := json.Unmarshal(mutant.Proof, proof); err != nil {
+			return Result{}, err
+		}
+		accepted, panicked := verify(c, proof)
+		name := fmt.Sprintf("%s/%s", mutant.Field, mutant.Mutation)
+		if accepted {
+			result.Accepted = append(result.Accepted, name)
+		}
+		if panicked {
+			result.Panicked = append(result.Panicked, name)
+		}
+		result.Mutants++
+	}
+	return result, nil
+}
+
+// Check that the valid proof is accepted and every mutant is rejected without a panic
+func (result *Result) Passed() bool {
+	return result.Valid && len(result.Accepted) == 0 && len(result.Panicked) == 0
+}
+
+// Load the corpus of a protocol, returning false if it does not exist
+func LoadCorpus(dir string, protocol string) (Corpus, bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, protocol+".json"))
+	if os.IsNotExist(err) {
+		return Corpus{}, false, nil
+	}
+	if err != nil {
+		return Corpus{}, false, err
+	}
+	var corpus Corpus
+	if err := json.Unmarshal(data, &corpus); err != nil {
+		return Corpus{}, false, err
+	}
+	return corpus, true, nil
+}
+
+// Store the corpus of a protocol
+func (corpus *Corpus) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(corpus)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, corpus.Protocol+".json"), append(data, '\n'), 0644)
+}
+
+////////////////////////Private functions
+
+// Run the verifier on a proof, recovering from a panic
+func verify(c Case, proof interface{}) (accepted bool, panicked bool) {
+	defer func() {
+		if recover() != nil {
+			accepted, panicked = false, true
+		}
+	}()
+	return c.Verify(proof), false
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Replay the stored corpus of every protocol: the valid proof is accepted and every mutant rejected
+func TestCorpus(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	for _, p := range protocols {
+		corpus, ok, err := LoadCorpus("corpus", p.name)
+		if err != nil {
+			t.Fatalf("%s: cannot load the corpus: %v", p.name, err)
+		}
+		if !ok {
+			t.Fatalf("%s: the corpus is missing", p.name)
+		}
+		result, err := corpus.Check(p.empty())
+		if err != nil {
+			t.Fatalf("%s: cannot decode the corpus: %v", p.name, err)
+		}
+		if !result.Valid {
+			t.Errorf("%s: the valid proof is rejected", p.name)
+		}
+		if result.Mutants == 0 {
+			t.Errorf("%s: the corpus has no mutants", p.name)
+		}
+		for _, mutant := range result.Accepted {
+			t.Errorf("%s: accepted %s", p.name, mutant)
+		}
+		for _, mutant := range result.Panicked {
+			t.Errorf("%s: panicked %s", p.name, mutant)
+		}
+	}
+}
+
+// Fresh adaptive forgeries satisfy the unbound equation by construction and are rejected by the verifier
+func TestForgeries(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	c, err := newInnerProductCase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := c.(Forger).Forgeries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	corpus := Corpus{Protocol: "inner_product", Mutants: forged}
+	corpus.Case, _ = json.Marshal(c)
+	result, err := corpus.Check(&innerProductCase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid || result.Mutants != len(forged) || len(forged) == 0 {
+		t.Fatalf("valid proof accepted: %v, %d of %d forgeries checked", result.Valid, result.Mutants, len(forged))
+	}
+	for _, mutant := range append(result.Accepted, result.Panicked...) {
+		t.Errorf("forgery %s not rejected", mutant)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Case with adversarial proofs beyond the field mutations, stored in the corpus with the mutants
+type Forger interface {
+	Forgeries() ([]Mutant, error)
+}
+
+// Forge an inner-product argument for P with <a, b> = c against round challenges x_j = H(L_j + R_j), which do not
+// depend on the transcript. The prover fixes S_j = L_j + R_j first, so x_j is known before L_j, then picks a, b and
+// all L_j but the first at random and solves L_0, R_j = S_j - L_j from the verification equation
+//
+//	g^{a s} h^{b s'} v^{ab} L^{-x} R^{-x^{-1}} = P v^c
+//
+// where sum_j x_j L_j + x_j^{-1} R_j = sum_j (x_j - x_j^{-1}) L_j + x_j^{-1} S_j. Without knowing any opening of P
+// the forgery satisfies that equation, so a verifier deriving x_j from the transcript has to reject it
+func forgeInnerProduct(G []utils.Point, H []utils.Point, v utils.Point, P utils.Point, c *big.Int) ([]utils.Point, []utils.Point, *big.Int, *big.Int, error) {
+	var S []utils.Point
+	var x []*big.Int
+	for n := len(G); n > 1; n = n/2 + n%2 {
+		S = append(S, utils.GeneratePoint())
+		x = append(x, unboundChallenge(S[len(S)-1]))
+	}
+	if len(S) == 0 {
+		return nil, nil, nil, nil, errors.New("an argument of length 1 has no rounds to forge")
+	}
+	s, inv_s := foldExponents(x, len(G))
+	a, b := randomScalar(), randomScalar()
+
+	// K - P v^c - sum_j x_j^{-1} S_j - sum_{j>0} (x_j - x_j^{-1}) L_j = (x_0 - x_0^{-1}) L_0
+	var me utils.Multi_Exp
+	me.Add_Terms(G, utils.Cal_Sca_Vec(s, a))
+	me.Add_Terms(H, utils.Cal_Sca_Vec(inv_s, b))
+	me.Add_Term(v, utils.Sub_In_P(utils.Mul_In_P(a, b), c))
+	me.Add_Term(P, utils.Neg_Zp(big.NewInt(1)))
+	L := make([]utils.Point, len(S))
+	for j := range S {
+		inv_x := utils.Inverse_Zp(x[j])
+		me.Add_Term(S[j], utils.Neg_Zp(inv_x))
+		if j > 0 {
+			L[j] = utils.GeneratePoint()
+			me.Add_Term(L[j], utils.Neg_Zp(utils.Sub_In_P(x[j], inv_x)))
+		}
+	}
+	L[0] = utils.Commit(me.Calculate(), utils.Inverse_Zp(utils.Sub_In_P(x[0], utils.Inverse_Zp(x[0]))))
+	var R []utils.Point
+	for j := range S {
+		R = append(R, utils.Cal_Point_Add(S[j], utils.Commit(L[j], utils.Neg_Zp(big.NewInt(1)))))
+	}
+
+	// The forgery should pass the unbound verification equation
+	var check utils.Multi_Exp
+	check.Add_Terms(G, utils.Cal_Sca_Vec(s, a))
+	check.Add_Terms(H, utils.Cal_Sca_Vec(inv_s, b))
+	check.Add_Term(v, utils.Sub_In_P(utils.Mul_In_P(a, b), c))
+	for j := range S {
+		check.Add_Term(L[j], utils.Neg_Zp(x[j]))
+		check.Add_Term(R[j], utils.Neg_Zp(utils.Inverse_Zp(x[j])))
+	}
+	if !utils.Is_Equal_Point(check.Calculate(), P) {
+		return nil, nil, nil, nil, errors.New("the forged argument does not satisfy the unbound equation")
+	}
+	return L, R, a, b, nil
+}
+
+// Encode n forged inner-product arguments of a case as mutants
+func forgedMutants(n int, forge func() (interface{}, error)) ([]Mutant, error) {
+	var mutants []Mutant
+	for i := 0; i < n; i++ {
+		proof, err := forge()
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(proof)
+		if err != nil {
+			return nil, err
+		}
+		mutants = append(mutants, Mutant{Field: "IPA", Mutation: fmt.Sprintf("forged_rounds_%d", i), Proof: encoded})
+	}
+	return mutants, nil
+}
+
+////////////////////////Private functions
+
+// Round challenge x = H(S) of the unbound argument
+func unboundChallenge(S utils.Point) *big.Int {
+	hash := sha256.Sum256(S.Point2Bytes())
+	return new(big.Int).Mod(new(big.Int).SetBytes(hash[:]), utils.Curve.N)
+}
+
+// Exponents s_i, s_i^{-1} of the generators after folding N generators with the round challenges x, carrying the
+// odd element of every round
+func foldExponents(x []*big.Int, N int) ([]*big.Int, []*big.Int) {
+	var lens []int
+	for n := N; n > 1; n = n/2 + n%2 {
+		lens = append(lens, n)
+	}
+	s := []*big.Int{big.NewInt(1)}
+	inv_s := []*big.Int{big.NewInt(1)}
+	for j := len(x) - 1; j >= 0; j-- {
+		n, h := lens[j], lens[j]/2
+		inv_x := utils.Inverse_Zp(x[j])
+		s_prev := make([]*big.Int, n)
+		inv_s_prev := make([]*big.Int, n)
+		for i := 0; i < h; i++ {
+			s_prev[i] = utils.Mul_In_P(s[i], inv_x)
+			s_prev[h+i] = s[i]
+			inv_s_prev[i] = utils.Mul_In_P(inv_s[i], x[j])
+			inv_s_prev[h+i] = inv_s[i]
+		}
+		if n%2 == 1 {
+			s_prev[n-1] = s[h]
+			inv_s_prev[n-1] = inv_s[h]
+		}
+		s, inv_s = s_prev, inv_s_prev
+	}
+	return s, inv_s
+}
+
+// Sample a random element of Zp
+func randomScalar() *big.Int {
+	return utils.Add_In_P(utils.Generate_Random_Zp(256), big.NewInt(0))
+}
@@ -0,0 +1,93 @@
+// Command prooffuzz mutates valid any_proofs, range_proofs, omniring and inner-product proofs field by field
+// (points, scalars, vector entries and lengths) and checks that the verifier rejects every mutant. Inner-product
+// arguments are also forged adaptively, solving L_j, R_j after fixing L_j + R_j. The mutants are stored as a
+// regression corpus, which is replayed on every run
+//
+//	go run ./prooffuzz [-protocol any_proofs|range_proofs|omniring|inner_product|all] [-corpus dir] [-update]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+func main() {
+	protocol := flag.String("protocol", "all", "protocol to fuzz: any_proofs, range_proofs, omniring, inner_product or all")
+	dir := flag.String("corpus", "prooffuzz/corpus", "directory of the regression corpus")
+	update := flag.Bool("update", false, "store the mutants of this run as the regression corpus")
+	flag.Parse()
+
+	utils.Curve = secp256k1.S256()
+	passed := true
+	found := false
+	for _, p := range protocols {
+		if *protocol != "all" && *protocol != p.name {
+			continue
+		}
+		found = true
+
+		// Replay the stored corpus
+		stored, ok, err := LoadCorpus(*dir, p.name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: cannot load the corpus: %v\n", p.name, err)
+			os.Exit(2)
+		}
+		if ok {
+			result, err := stored.Check(p.empty())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: cannot decode the corpus: %v\n", p.name, err)
+				os.Exit(2)
+			}
+			passed = report(p.name+" (corpus)", result) && passed
+		}
+
+		// Mutate a fresh proof
+		c, err := p.generate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: cannot generate a proof: %v\n", p.name, err)
+			os.Exit(2)
+		}
+		corpus, err := NewCorpus(p.name, c)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: cannot mutate the proof: %v\n", p.name, err)
+			os.Exit(2)
+		}
+		result, err := corpus.Check(p.empty())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: cannot decode the mutants: %v\n", p.name, err)
+			os.Exit(2)
+		}
+		passed = report(p.name, result) && passed
+		if *update {
+			if err := corpus.Save(*dir); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: cannot store the corpus: %v\n", p.name, err)
+				os.Exit(2)
+			}
+		}
+	}
+	if !found {
+		fmt.Fprintf(os.Stderr, "unknown protocol %q\n", *protocol)
+		os.Exit(2)
+	}
+	if !passed {
+		os.Exit(1)
+	}
+}
+
+// Print the result of a run and return whether it passed
+func report(name string, result Result) bool {
+	fmt.Printf("%s: valid proof accepted: %v, %d mutants, %d accepted, %d panicked\n",
+		name, result.Valid, result.Mutants, len(result.Accepted), len(result.Panicked))
+	for _, mutant := range result.Accepted {
+		fmt.Printf("\taccepted %s\n", mutant)
+	}
+	for _, mutant := range result.Panicked {
+		fmt.Printf("\tpanicked %s\n", mutant)
+	}
+	return result.Passed()
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"anyOutOfMany/utils"
+)
+
+// Proof with one field changed
+type Mutant struct {
+	Field    string          // path of the mutated field, e.g., L[1]
+	Mutation string          // name of the mutation
+	Proof    json.RawMessage // mutated proof
+}
+
+var pointType = reflect.TypeOf(utils.Point{})
+var scalarType = reflect.TypeOf((*big.Int)(nil))
+
+// Mutate every field of the proof, a pointer to a struct of points, scalars and vectors of them, in every way
+// that changes its encoding
+func Mutants(proof interface{}) ([]Mutant, error) {
+	original, err := json.Marshal(proof)
+	if err != nil {
+		return nil, err
+	}
+	typ := reflect.TypeOf(proof).Elem()
+	var mutants []Mutant
+	add := func(field string, mutation string, change func(v reflect.Value)) error {
+		clone := reflect.New(typ)
+		if err := json.Unmarshal(original, clone.Interface()); err != nil {
+			return err
+		}
+		change(clone.Elem())
+		encoded, err := json.Marshal(clone.Interface())
+		if err != nil {
+			return err
+		}
+		if string(encoded) != string(original) {
+			mutants = append(mutants, Mutant{Field: field, Mutation: mutation, Proof: encoded})
+		}
+		return nil
+	}
+
+	value := reflect.ValueOf(proof).Elem()
+	for f := 0; f < typ.NumField(); f++ {
+		name := typ.Field(f).Name
+		switch field := value.Field(f); {
+		case field.Type() == pointType || field.Type() == scalarType:
+			for _, mutation := range elementMutations(field.Type(), false) {
+				m := mutation
+				if err := add(name, m.name, func(v reflect.Value) { m.apply(v.Field(f)) }); err != nil {
+					return nil, err
+				}
+			}
+		case field.Kind() == reflect.Slice && (field.Type().Elem() == pointType || field.Type().Elem() == scalarType):
+			for i := 0; i < field.Len(); i++ {
+				for _, mutation := range elementMutations(field.Type().Elem(), true) {
+					m, index := mutation, i
+					if err := add(fmt.Sprintf("%s[%d]", name, i), m.name, func(v reflect.Value) { m.apply(v.Field(f).Index(index)) }); err != nil {
+						return nil, err
+					}
+				}
+			}
+			for _, mutation := range lengthMutations {
+				m := mutation
+				if err := add(name, m.name, func(v reflect.Value) { m.apply(v.Field(f)) }); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return mutants, nil
+}
+
+////////////////////////Private functions
+
+type mutation struct {
+	name  string
+	apply func(v reflect.Value)
+}
+
+// Mutations of a point or scalar, vector entries get a smaller set
+func elementMutations(typ reflect.Type, entry bool) []mutation {
+	if typ == pointType {
+		mutations := []mutation{
+			{"random_point", func(v reflect.Value) { v.Set(reflect.ValueOf(utils.GeneratePoint())) }},
+			{"off_curve", func(v reflect.Value) {
+				p := v.Interface().(utils.Point)
+				v.Set(reflect.ValueOf(utils.Point{X: p.X, Y: new(big.Int).Add(p.Y, big.NewInt(1))}))
+			}},
+		}
+		if entry {
+			return mutations
+		}
+		return append(mutations,
+			mutation{"negate", func(v reflect.Value) {
+				p := v.Interface().(utils.Point)
+				v.Set(reflect.ValueOf(utils.Point{X: p.X, Y: new(big.Int).Sub(utils.Curve.P, p.Y)}))
+			}},
+			mutation{"identity", func(v reflect.Value) { v.Set(reflect.ValueOf(utils.Point{X: big.NewInt(0), Y: big.NewInt(0)})) }},
+			mutation{"nil", func(v reflect.Value) { v.Set(reflect.ValueOf(utils.Point{})) }},
+		)
+	}
+	mutations := []mutation{
+		{"add_one", func(v reflect.Value) {
+			v.Set(reflect.ValueOf(utils.Add_In_P(v.Interface().(*big.Int), big.NewInt(1))))
+		}},
+		{"add_order", func(v reflect.Value) {
+			v.Set(reflect.ValueOf(new(big.Int).Add(v.Interface().(*big.Int), utils.Curve.N)))
+		}},
+	}
+	if entry {
+		return mutations
+	}
+	return append(mutations,
+		mutation{"zero", func(v reflect.Value) { v.Set(reflect.ValueOf(big.NewInt(0))) }},
+		mutation{"negate", func(v reflect.Value) { v.Set(reflect.ValueOf(utils.Neg_Zp(v.Interface().(*big.Int)))) }},
+		mutation{"nil", func(v reflect.Value) { v.Set(reflect.Zero(scalarType)) }},
+	)
+}
+
+// Mutations of the length of a vector
+var lengthMutations = []mutation{
+	{"drop_last", func(v reflect.Value) {
+		if v.Len() > 0 {
+			v.Set(v.Slice(0, v.Len()-1))
+		}
+	}},
+	{"repeat_last", func(v reflect.Value) {
+		if v.Len() > 0 {
+			v.Set(reflect.Append(v, v.Index(v.Len()-1)))
+		}
+	}},
+	{"append_random", func(v reflect.Value) {
+		if v.Type().Elem() == pointType {
+			v.Set(reflect.Append(v, reflect.ValueOf(utils.GeneratePoint())))
+		} else {
+			v.Set(reflect.Append(v, reflect.ValueOf(utils.Add_In_P(utils.Generate_Random_Zp(256), big.NewInt(0)))))
+		}
+	}},
+	{"empty", func(v reflect.Value) { v.Set(reflect.Zero(v.Type())) }},
+}
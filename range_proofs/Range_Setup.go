@@ -1,10 +1,7 @@
 package range_proofs
 
 import (
-	"crypto/sha256"
 	"math/big"
-	"math/rand"
-	"time"
 
 	"anyOutOfMany/utils"
 )
@@ -24,16 +21,13 @@ type Transcript struct {
 	Z         *big.Int
 }
 
-//Generete vector b_0 for partial knowledge proofs
-
-func Generate_b_0(d int) []*big.Int {
+//Generete vector b_0 as the d-bit binary decomposition of the secret value, least significant bit first
+func Generate_b_0(value *big.Int, d int) []*big.Int {
 
 	var b_0 []*big.Int
 
 	for j := 0; j < d; j++ {
-		rand.Seed(time.Now().Unix() + int64(j)) // unix 时间戳，秒
-		data := rand.Int63n(2)
-		b_0 = append(b_0, big.NewInt(data))
+		b_0 = append(b_0, big.NewInt(int64(value.Bit(j))))
 	}
 
 	return b_0
@@ -110,20 +104,23 @@ func Generate_Public_Coin(g utils.Point, h utils.Point, d int, bit []*big.Int, g
 	return public_coin
 }
 
-// Generate challenge
-func Generate_YZ(A utils.Point, B utils.Point) (*big.Int, *big.Int) {
-	AB1 := utils.Cal_Point_Add(A, utils.Cal_Point_Sca(B, big.NewInt(1)))
-	AB2 := utils.Cal_Point_Add(A, utils.Cal_Point_Sca(B, big.NewInt(2)))
-	y32 := sha256.Sum256(AB1.Point2Bytes())
-	y := big.NewInt(0).SetBytes(y32[:])
-	z32 := sha256.Sum256(AB2.Point2Bytes())
-	z := big.NewInt(0).SetBytes(z32[:])
-	return y, z
+//Generete the d-bit binary decomposition of a value, least significant bit first
+func Generate_Value_Bits(value uint64, d int) []*big.Int {
+	var bits []*big.Int
+	for i := 0; i < d; i++ {
+		bits = append(bits, big.NewInt(int64((value>>uint(i))&1)))
+	}
+	return bits
 }
 
-func Generate_X(T1 utils.Point, T2 utils.Point) *big.Int {
-	T1T2 := utils.Cal_Point_Add(T1, T2)
-	x32 := sha256.Sum256(T1T2.Point2Bytes())
-	x := big.NewInt(0).SetBytes(x32[:])
-	return x
+//Generete the aggregation vector (z^2 \cdot 2^d, z^3 \cdot 2^d, ..., z^{m+1} \cdot 2^d) for m values of width d
+func Generate_Aggregate_Vector(z *big.Int, d int, m int) []*big.Int {
+	var Scalar_Vector []*big.Int
+	vec_2N := Generate_Exp_Scalar_Vector(big.NewInt(2), d)
+	zj := utils.Mul_In_P(z, z)
+	for j := 0; j < m; j++ {
+		Scalar_Vector = append(Scalar_Vector, utils.Cal_Sca_Vec(vec_2N, zj)...)
+		zj = utils.Mul_In_P(zj, z)
+	}
+	return Scalar_Vector
 }
@@ -0,0 +1,206 @@
+package range_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Public parameters of range proofs, m values of width d use the first d*m generators of each vector
+type Params struct {
+	Gen_g, Gen_h         utils.Point   // generators g,h of the value commitments
+	Gen_Vec_G, Gen_Vec_H []utils.Point // generator vector g h
+}
+
+// Initialization function
+func (params *Params) New(G utils.Point, H utils.Point, G_Vector []utils.Point, H_Vector []utils.Point) {
+	params.Gen_g = G
+	params.Gen_h = H
+	params.Gen_Vec_G = G_Vector
+	params.Gen_Vec_H = H_Vector
+}
+
+// Generate public parameters serving every width d <= max_d for up to max_m aggregated values,
+// a proof of m values of width d only uses the first d*m generators
+func (params *Params) Setup(max_d int, max_m int) {
+	params.New(utils.GeneratePoint(), utils.GeneratePoint(), utils.GenerateMultiPoint(max_d*max_m), utils.GenerateMultiPoint(max_d*max_m))
+}
+
+// Largest number of bits d*m the parameters can serve
+func (params *Params) Capacity() int {
+	if len(params.Gen_Vec_G) < len(params.Gen_Vec_H) {
+		return len(params.Gen_Vec_G)
+	}
+	return len(params.Gen_Vec_H)
+}
+
+// Commit to a value: V = g^v h^gamma
+func (params *Params) Commit(value uint64, blinding *big.Int) utils.Point {
+	return utils.Pedersen_Commit(params.Gen_g, params.Gen_h, new(big.Int).SetUint64(value), blinding)
+}
+
+// Build the statement of existing commitments V_j = g^{v_j} h^{gamma_j} for values of width d
+func (params *Params) Statement(commitments []utils.Point, d int) Statement {
+	return Statement{
+		Gen_g:        params.Gen_g,
+		Gen_h:        params.Gen_h,
+		Gen_Vec_G:    params.Gen_Vec_G,
+		Gen_Vec_H:    params.Gen_Vec_H,
+		Pub_Vec_Coin: commitments,
+		D:            d,
+	}
+}
+
+// Generate a proof that value lies in [0, 2^d) for the commitment V = g^value h^blinding
+func (params *Params) Prove(value uint64, blinding *big.Int, d int) (Statement, Proof, error) {
+	return params.ProveMultiple([]uint64{value}, []*big.Int{blinding}, d)
+}
+
+// Generate one aggregated proof that every value lies in [0, 2^d), the commitments V_j = g^{v_j} h^{gamma_j} are returned in the statement
+func (params *Params) ProveMultiple(values []uint64, blindings []*big.Int, d int) (Statement, Proof, error) {
+	return params.proveMultiple(values, blindings, d, nil)
+}
+
+// Same as ProveMultiple with nonces derived from the values, blindings, statement and the optional extra randomness aux,
+// so identical inputs give identical proofs
+func (params *Params) DeterministicProveMultiple(values []uint64, blindings []*big.Int, d int, aux []byte) (Statement, Proof, error) {
+	return params.proveMultiple(values, blindings, d, utils.New_Nonce_Source(aux))
+}
+
+////////////////////////Private functions
+
+// Check the inputs of an aggregated proof and prove the bits of the values, drawing the nonces from nonces
+func (params *Params) proveMultiple(values []uint64, blindings []*big.Int, d int, nonces *utils.Nonce_Source) (Statement, Proof, error) {
+	m := len(values)
+	if m == 0 || len(blindings) != m {
+		return Statement{}, Proof{}, errors.New("the number of values and blinding factors should be equal and positive")
+	}
+	if d <= 0 || d > 64 {
+		return Statement{}, Proof{}, errors.New("the value width should be between 1 and 64")
+	}
+	if params.Capacity() < d*m {
+		return Statement{}, Proof{}, errors.New("the generator vectors are shorter than d*m")
+	}
+	var b_0 []*big.Int
+	for j := 0; j < m; j++ {
+		if !utils.Is_Valid_Scalar(blindings[j]) {
+			return Statement{}, Proof{}, errors.New("the blinding factor should be an element of Zp")
+		}
+		if d < 64 && values[j]>>uint(d) != 0 {
+			return Statement{}, Proof{}, errors.New("the value should be smaller than 2^d")
+		}
+		b_0 = append(b_0, Generate_Value_Bits(values[j], d)...)
+	}
+	statement, proof := params.proveBits(b_0, blindings, d, nil, nonces)
+	return statement, proof, nil
+}
+
+// Prove that b_0 consists of m binary blocks of width d, the j-th block opening V_j with blinding gamma_j,
+// the interval bounds (if any) are bound into the transcript. The nonces are drawn from nonces, or crypto/rand if it is nil
+func (params *Params) proveBits(b_0 []*big.Int, gammas []*big.Int, d int, bounds []*big.Int, nonces *utils.Nonce_Source) (Statement, Proof) {
+	m := len(gammas)
+	nm := d * m
+	Vec_G := params.Gen_Vec_G[:nm]
+	Vec_H := params.Gen_Vec_H[:nm]
+
+	// Compute the commitments V_j
+	var pub_Vec_Coin []utils.Point
+	for j := 0; j < m; j++ {
+		pub_Vec_Coin = append(pub_Vec_Coin, Generate_Public_Coin(params.Gen_g, params.Gen_h, d, b_0[j*d:(j+1)*d], gammas[j]))
+	}
+	statement := params.Statement(pub_Vec_Coin, d)
+	statement.Bounds = bounds
+	nonces.Bind(*statement.newTranscript(), append(append([]*big.Int{}, b_0...), gammas...))
+
+	//Generate commitments A, B
+	b_1 := Generate_b_1(b_0)
+	s_0 := nonces.Scalar_Vector(nm)
+	s_1 := nonces.Scalar_Vector(nm)
+	alpha := nonces.Scalar()
+	beta := nonces.Scalar()
+	A := utils.Cal_Point_Add(utils.Pedersen_Commit_Vector(Vec_G, Vec_H, b_0, b_1), utils.Commit(params.Gen_h, alpha))
+	B := utils.Cal_Point_Add(utils.Pedersen_Commit_Vector(Vec_G, Vec_H, s_0, s_1), utils.Commit(params.Gen_h, beta))
+
+	//Generate challenges y,z
+	fs := statement.newTranscript()
+	fs.Append_Point("A", A)
+	fs.Append_Point("B", B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+
+	// l(x) = b0 + z1^{nm} + s0 x, r(x) = y^{nm} \circ (b1 + z1^{nm} + s1 x) + \sum_j z^{j+2} 2^d_j
+	yN := Generate_Exp_Scalar_Vector(y, nm)
+	z1N := Generate_Scalar_Vector(z, nm)
+	z2N := Generate_Aggregate_Vector(z, d, m)
+	l_0 := utils.Cal_Add_Vec(b_0, z1N)
+	r_0 := utils.Cal_Add_Vec(utils.Cal_HP_Vec(yN, utils.Cal_Add_Vec(b_1, z1N)), z2N)
+	r_1 := utils.Cal_HP_Vec(yN, s_1)
+
+	//Compute T1, T2 with t1 = <l_0, r_1> + <s_0, r_0>, t2 = <s_0, r_1>
+	t_1 := utils.Add_In_P(utils.Cal_IP_Vec(l_0, r_1), utils.Cal_IP_Vec(s_0, r_0))
+	t_2 := utils.Cal_IP_Vec(s_0, r_1)
+	tau_1 := nonces.Scalar()
+	tau_2 := nonces.Scalar()
+	T1 := utils.Pedersen_Commit(params.Gen_g, params.Gen_h, t_1, tau_1)
+	T2 := utils.Pedersen_Commit(params.Gen_g, params.Gen_h, t_2, tau_2)
+
+	//Generate challenge x
+	fs.Append_Point("T1", T1)
+	fs.Append_Point("T2", T2)
+	x := fs.Challenge("x")
+
+	//Compute zeta = l(x), eta = r(x) and t = <zeta, eta>
+	zeta := utils.Cal_Add_Vec(l_0, utils.Cal_Sca_Vec(s_0, x))
+	eta := utils.Cal_Add_Vec(r_0, utils.Cal_Sca_Vec(r_1, x))
+	ip := utils.Cal_IP_Vec(zeta, eta)
+
+	//Compute tau_x = tau_1 x + tau_2 x^2 + \sum_j z^{j+2} gamma_j and mu = alpha + beta x
+	x2 := utils.Mul_In_P(x, x)
+	tau_x := utils.Add_In_P(utils.Mul_In_P(tau_1, x), utils.Mul_In_P(tau_2, x2))
+	zj := utils.Mul_In_P(z, z)
+	for j := 0; j < m; j++ {
+		tau_x = utils.Add_In_P(tau_x, utils.Mul_In_P(zj, gammas[j]))
+		zj = utils.Mul_In_P(zj, z)
+	}
+	mu := utils.Add_In_P(alpha, utils.Mul_In_P(beta, x))
+
+	// Compress the openings over generators g and h^{y^{-nm}}
+	Inv_yN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), nm)
+	Inv_Vec_H := utils.Generate_Point_Vector_with_y(Vec_H, Inv_yN)
+	L, R, C_zeta, C_eta := utils.Opt_Prove(fs, zeta, eta, Vec_G, Inv_Vec_H, params.Gen_g)
+
+	proof := Proof{
+		A:      A,
+		B:      B,
+		T1:     T1,
+		T2:     T2,
+		Tau_x:  tau_x,
+		Mu:     mu,
+		Ip:     ip,
+		L:      L,
+		R:      R,
+		C_zeta: C_zeta[0],
+		C_eta:  C_eta[0],
+	}
+	return statement, proof
+}
+
+// Start the Fiat-Shamir transcript of a statement, binding the generators, the first d*m generators of each
+// vector, the width, the commitments and the interval bounds
+func (statement *Statement) newTranscript() *utils.Fiat_Shamir {
+	var fs utils.Fiat_Shamir
+	nm := statement.D * len(statement.Pub_Vec_Coin)
+	fs.New("range_proofs")
+	fs.Append_Point("g", statement.Gen_g)
+	fs.Append_Point("h", statement.Gen_h)
+	fs.Append_Points("G", utils.Point_Prefix(statement.Gen_Vec_G, nm))
+	fs.Append_Points("H", utils.Point_Prefix(statement.Gen_Vec_H, nm))
+	fs.Append_Int("d", statement.D)
+	fs.Append_Points("V", statement.Pub_Vec_Coin)
+	fs.Append_Int("bounds", len(statement.Bounds))
+	for _, bound := range statement.Bounds {
+		fs.Append_Scalar("bound", bound)
+	}
+	return &fs
+}
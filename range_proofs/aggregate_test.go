@@ -0,0 +1,124 @@
+package range_proofs
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Aggregated proofs of m values, m not necessarily a power of two
+func TestProveMultiple(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	var params Params
+	params.Setup(8, 7)
+	tests := []struct {
+		values []uint64
+		d      int
+	}{
+		{[]uint64{0}, 8},
+		{[]uint64{255, 1}, 8},
+		{[]uint64{3, 0, 7}, 3},
+		{[]uint64{1, 2, 3, 4, 5}, 8},
+		{[]uint64{1, 0, 1, 1, 0, 0, 1}, 1},
+	}
+	for _, test := range tests {
+		m := len(test.values)
+		statement, proof, err := params.ProveMultiple(test.values, utils.Generate_Random_Zp_Vector(m, 256), test.d)
+		if err != nil {
+			t.Fatalf("m = %d: %v", m, err)
+		}
+		if err := Verify(statement, proof); err != nil {
+			t.Errorf("m = %d: honest proof rejected: %v", m, err)
+		}
+
+		// Rejections: a dropped commitment, a commitment to another value and another width
+		dropped := statement
+		dropped.Pub_Vec_Coin = statement.Pub_Vec_Coin[:m-1]
+		if m > 1 && Verify(dropped, proof) == nil {
+			t.Errorf("m = %d: proof accepted without its last commitment", m)
+		}
+		shifted := statement
+		shifted.Pub_Vec_Coin = append([]utils.Point{}, statement.Pub_Vec_Coin...)
+		shifted.Pub_Vec_Coin[m-1] = utils.Cal_Point_Add(shifted.Pub_Vec_Coin[m-1], params.Gen_g)
+		if Verify(shifted, proof) == nil {
+			t.Errorf("m = %d: proof accepted for a shifted value", m)
+		}
+		widened := statement
+		widened.D = test.d + 1
+		if Verify(widened, proof) == nil {
+			t.Errorf("m = %d: proof accepted for another width", m)
+		}
+	}
+}
+
+func TestProveMultipleInputs(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	var params Params
+	params.Setup(8, 3)
+	blindings := utils.Generate_Random_Zp_Vector(3, 256)
+	tests := []struct {
+		name      string
+		values    []uint64
+		blindings []*big.Int
+		d         int
+	}{
+		{"value out of range", []uint64{1, 256, 2}, blindings, 8},
+		{"missing blinding", []uint64{1, 2, 3}, blindings[:2], 8},
+		{"no values", nil, nil, 8},
+		{"zero width", []uint64{0}, blindings[:1], 0},
+		{"blinding outside Zp", []uint64{1}, []*big.Int{utils.Curve.Params().N}, 8},
+	}
+	for _, test := range tests {
+		if _, _, err := params.ProveMultiple(test.values, test.blindings, test.d); err == nil {
+			t.Errorf("%s: proof generated", test.name)
+		}
+	}
+}
+
+// Widths that are not powers of two use exactly ceil(log2 d) rounds, and widths beyond the capacity are refused
+func TestWidthCapacity(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	var params Params
+	params.Setup(52, 1)
+	tests := []struct {
+		d      int
+		m      int
+		rounds int
+	}{
+		{40, 1, 6}, {52, 1, 6}, {17, 3, 6}, {26, 2, 6}, {5, 1, 3},
+	}
+	for _, test := range tests {
+		statement, proof, err := params.ProveMultiple(make([]uint64, test.m), utils.Generate_Random_Zp_Vector(test.m, 256), test.d)
+		if err != nil {
+			t.Fatalf("d = %d, m = %d: %v", test.d, test.m, err)
+		}
+		if len(proof.L) != test.rounds {
+			t.Errorf("d = %d, m = %d: %d rounds, want %d", test.d, test.m, len(proof.L), test.rounds)
+		}
+		if err := Verify(statement, proof); err != nil {
+			t.Errorf("d = %d, m = %d: honest proof rejected: %v", test.d, test.m, err)
+		}
+	}
+
+	overflows := []struct {
+		d int
+		m int
+	}{
+		{53, 1}, {27, 2}, {40, 2}, {65, 1},
+	}
+	for _, test := range overflows {
+		if _, _, err := params.ProveMultiple(make([]uint64, test.m), utils.Generate_Random_Zp_Vector(test.m, 256), test.d); err == nil {
+			t.Errorf("d = %d, m = %d: proof generated beyond the capacity %d", test.d, test.m, params.Capacity())
+		}
+	}
+
+	// A statement needing more generators than it carries is rejected
+	statement, proof, _ := params.Prove(1, utils.Generate_Random_Zp(256), 52)
+	statement.Gen_Vec_G = statement.Gen_Vec_G[:51]
+	if Verify(statement, proof) == nil {
+		t.Error("proof accepted with too few generators")
+	}
+}
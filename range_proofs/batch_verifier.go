@@ -0,0 +1,151 @@
+package range_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Public statement of a range proof: generators, the committed values V_j and the bit width d
+type Statement struct {
+	Gen_g, Gen_h         utils.Point   // generators g,h
+	Gen_Vec_G, Gen_Vec_H []utils.Point // generator vector g h, only the first D*m are used
+	Pub_Vec_Coin         []utils.Point // commitments V_j = g^{v_j} h^{gamma_j}
+	D                    int           // value width d
+	Bounds               []*big.Int    // interval [a, b] of an interval proof, empty otherwise
+}
+
+// Non-interactive (aggregated) range proof, the challenges are recomputed by the verifier
+type Proof struct {
+	A, B, T1, T2  utils.Point
+	Tau_x, Mu, Ip *big.Int
+	L, R          []utils.Point // inner-product argument rounds
+	C_zeta, C_eta *big.Int      // compressed openings zeta, eta
+}
+
+// Error returned by BatchVerify, listing the indices of the invalid proofs
+type BatchError = utils.Batch_Error
+
+// Verify a single range proof
+func Verify(statement Statement, proof Proof) error {
+	if err := BatchVerify([]Statement{statement}, []Proof{proof}); err != nil {
+		return errors.New("invalid range proof")
+	}
+	return nil
+}
+
+// Verify many range proofs, possibly of different widths and aggregation sizes, with one randomized multi-exponentiation,
+// the generator prefixes shared by the statements are multiplied only once
+func BatchVerify(statements []Statement, proofs []Proof) error {
+	if len(statements) != len(proofs) {
+		return errors.New("the number of statements and proofs should be equal")
+	}
+	return utils.Batch_Verify("range proofs", len(proofs), func(me *utils.Multi_Exp, i int) error {
+		return addVerifyTerms(me, statements[i], proofs[i])
+	})
+}
+
+////////////////////////Private functions
+
+// Check that a statement and proof are well formed
+func checkProofFormat(statement Statement, proof Proof) error {
+	nm := statement.D * len(statement.Pub_Vec_Coin)
+	if statement.D <= 0 || nm == 0 || len(statement.Gen_Vec_G) < nm || len(statement.Gen_Vec_H) < nm {
+		return errors.New("the generator vectors should be at least as long as the value width times the number of values")
+	}
+	if len(statement.Bounds) != 0 && len(statement.Bounds) != 2 {
+		return errors.New("an interval should have two bounds")
+	}
+	points := []utils.Point{statement.Gen_g, statement.Gen_h, proof.A, proof.B, proof.T1, proof.T2}
+	points = append(points, statement.Pub_Vec_Coin...)
+	points = append(points, statement.Gen_Vec_G[:nm]...)
+	points = append(points, statement.Gen_Vec_H[:nm]...)
+	points = append(points, proof.L...)
+	points = append(points, proof.R...)
+	for _, point := range points {
+		if !utils.Is_Valid_Point(point) {
+			return errors.New("the proof contains an invalid point")
+		}
+	}
+	for _, scalar := range []*big.Int{proof.Tau_x, proof.Mu, proof.Ip, proof.C_zeta, proof.C_eta} {
+		if !utils.Is_Valid_Scalar(scalar) {
+			return errors.New("the proof contains an invalid scalar")
+		}
+	}
+	return nil
+}
+
+// Add the two verification equations of one proof, each weighted by a fresh random value:
+// (1) g^{ip} h^{tau_x} = \prod_j V_j^{z^{j+2}} g^{delta} T1^x T2^{x^2}
+// (2) g^{a s} (h^{y^{-nm}})^{b s'} g^{x_ip (ab - ip)} L^{-x} R^{-x^{-1}} = A B^x g^z (h^{y^{-nm}})^{z y^{nm} + \sum_j z^{j+2} 2^d_j} h^{-mu}
+func addVerifyTerms(me *utils.Multi_Exp, statement Statement, proof Proof) error {
+	if err := checkProofFormat(statement, proof); err != nil {
+		return err
+	}
+	d := statement.D
+	m := len(statement.Pub_Vec_Coin)
+	nm := d * m
+
+	// Recompute the challenges
+	fs := statement.newTranscript()
+	fs.Append_Point("A", proof.A)
+	fs.Append_Point("B", proof.B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+	fs.Append_Point("T1", proof.T1)
+	fs.Append_Point("T2", proof.T2)
+	x := fs.Challenge("x")
+	x_ip, x_j, inv_x_j, s, inv_s, ok := utils.Opt_Challenges(fs, proof.Ip, proof.L, proof.R, nm)
+	if !ok {
+		return errors.New("the inner-product argument has a wrong number of rounds")
+	}
+	yN := Generate_Exp_Scalar_Vector(y, nm)
+	Inv_yN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), nm)
+	z2N := Generate_Aggregate_Vector(z, d, m)
+	vec_2N := Generate_Exp_Scalar_Vector(big.NewInt(2), d)
+	z2 := utils.Mul_In_P(z, z)
+	x2 := utils.Mul_In_P(x, x)
+
+	// delta = (z+z^2) <1^{nm}, y^{nm}> + \sum_j z^{j+3} <1^d, 2^d>
+	delta := utils.Mul_In_P(utils.Add_In_P(z, z2), utils.Cal_IP_Vec(Generate_Scalar_Vector(big.NewInt(1), nm), yN))
+	sum_2N := utils.Cal_IP_Vec(Generate_Scalar_Vector(big.NewInt(1), d), vec_2N)
+	zj := utils.Mul_In_P(z2, z)
+	for j := 0; j < m; j++ {
+		delta = utils.Add_In_P(delta, utils.Mul_In_P(zj, sum_2N))
+		zj = utils.Mul_In_P(zj, z)
+	}
+
+	// Random weights of the two equations
+	r_1 := utils.Generate_Random_Zp(q)
+	r_2 := utils.Generate_Random_Zp(q)
+	neg_r_2 := utils.Neg_Zp(r_2)
+
+	// Equation (1)
+	ab := utils.Mul_In_P(proof.C_zeta, proof.C_eta)
+	g_exp := utils.Add_In_P(utils.Mul_In_P(r_1, utils.Sub_In_P(proof.Ip, delta)), utils.Mul_In_P(r_2, utils.Mul_In_P(x_ip, utils.Sub_In_P(ab, proof.Ip))))
+	h_exp := utils.Add_In_P(utils.Mul_In_P(r_1, proof.Tau_x), utils.Mul_In_P(r_2, proof.Mu))
+	me.Add_Term(statement.Gen_g, g_exp)
+	me.Add_Term(statement.Gen_h, h_exp)
+	zj = z2
+	for j := 0; j < m; j++ {
+		me.Add_Term(statement.Pub_Vec_Coin[j], utils.Neg_Zp(utils.Mul_In_P(r_1, zj)))
+		zj = utils.Mul_In_P(zj, z)
+	}
+	me.Add_Term(proof.T1, utils.Neg_Zp(utils.Mul_In_P(r_1, x)))
+	me.Add_Term(proof.T2, utils.Neg_Zp(utils.Mul_In_P(r_1, x2)))
+
+	// Equation (2)
+	for i := 0; i < nm; i++ {
+		as_z := utils.Sub_In_P(utils.Mul_In_P(proof.C_zeta, s[i]), z)
+		bs := utils.Mul_In_P(utils.Mul_In_P(proof.C_eta, inv_s[i]), Inv_yN[i])
+		bs_z := utils.Sub_In_P(bs, utils.Add_In_P(z, utils.Mul_In_P(z2N[i], Inv_yN[i])))
+		me.Add_Term(statement.Gen_Vec_G[i], utils.Mul_In_P(r_2, as_z))
+		me.Add_Term(statement.Gen_Vec_H[i], utils.Mul_In_P(r_2, bs_z))
+	}
+	me.Add_Term(proof.A, neg_r_2)
+	me.Add_Term(proof.B, utils.Mul_In_P(neg_r_2, x))
+	me.Add_Terms(proof.L, utils.Cal_Sca_Vec(x_j, neg_r_2))
+	me.Add_Terms(proof.R, utils.Cal_Sca_Vec(inv_x_j, neg_r_2))
+	return nil
+}
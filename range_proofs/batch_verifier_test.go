@@ -0,0 +1,100 @@
+package range_proofs
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// A proof of the statement S1 is rejected for every statement S2 differing in one field
+func TestReplay(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	var params Params
+	params.Setup(8, 2)
+	statement, proof, err := params.ProveMultiple([]uint64{5, 200}, utils.Generate_Random_Zp_Vector(2, 256), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(statement, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+
+	mutations := map[string]func(statement *Statement){
+		"g":      func(statement *Statement) { statement.Gen_g = utils.GeneratePoint() },
+		"h":      func(statement *Statement) { statement.Gen_h = utils.GeneratePoint() },
+		"G":      func(statement *Statement) { statement.Gen_Vec_G = replacePoint(statement.Gen_Vec_G, 15) },
+		"H":      func(statement *Statement) { statement.Gen_Vec_H = replacePoint(statement.Gen_Vec_H, 0) },
+		"V":      func(statement *Statement) { statement.Pub_Vec_Coin = replacePoint(statement.Pub_Vec_Coin, 1) },
+		"d":      func(statement *Statement) { statement.D = 4 },
+		"bounds": func(statement *Statement) { statement.Bounds = []*big.Int{big.NewInt(0), big.NewInt(255)} },
+	}
+	for field, mutate := range mutations {
+		replayed := statement
+		mutate(&replayed)
+		if err := Verify(replayed, proof); err == nil {
+			t.Errorf("proof replayed against another %s accepted", field)
+		}
+	}
+}
+
+// Bisection reports exactly the tampered proofs of a batch of mixed widths
+func TestBatchVerify(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	var params Params
+	params.Setup(32, 2)
+	var statements []Statement
+	var proofs []Proof
+	for _, d := range []int{8, 32, 13, 8, 1, 20} {
+		statement, proof, err := params.Prove(1, utils.Generate_Random_Zp(256), d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statements = append(statements, statement)
+		proofs = append(proofs, proof)
+	}
+
+	tests := []struct {
+		name    string
+		invalid []int
+	}{
+		{"valid", nil},
+		{"first", []int{0}},
+		{"middle", []int{3}},
+		{"last", []int{5}},
+		{"two halves", []int{1, 4}},
+		{"adjacent", []int{2, 3}},
+		{"all", []int{0, 1, 2, 3, 4, 5}},
+	}
+	for _, test := range tests {
+		tampered := append([]Proof{}, proofs...)
+		for _, i := range test.invalid {
+			tampered[i].Tau_x = utils.Add_In_P(tampered[i].Tau_x, big.NewInt(1))
+		}
+		err := BatchVerify(statements, tampered)
+		if test.invalid == nil {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			continue
+		}
+		batch_err, ok := err.(*BatchError)
+		if !ok {
+			t.Errorf("%s: got %v, want a batch error", test.name, err)
+			continue
+		}
+		if fmt.Sprint(batch_err.Invalid) != fmt.Sprint(test.invalid) {
+			t.Errorf("%s: invalid proofs %v, want %v", test.name, batch_err.Invalid, test.invalid)
+		}
+	}
+
+	// A proof checked against another proof's statement is reported too
+	swapped := append([]Statement{}, statements...)
+	swapped[0], swapped[3] = swapped[3], swapped[0]
+	if err, ok := BatchVerify(swapped, proofs).(*BatchError); !ok || fmt.Sprint(err.Invalid) != "[0 3]" {
+		t.Errorf("swapped statements: got %v", err)
+	}
+}
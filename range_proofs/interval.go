@@ -0,0 +1,70 @@
+package range_proofs
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"anyOutOfMany/utils"
+)
+
+// Generate a proof that the value committed in V = g^v h^gamma lies in [a, b]. The two shifted commitments
+// V g^{-a} = g^{v-a} h^{gamma} and g^b V^{-1} = g^{b-v} h^{-gamma} are proven to lie in [0, 2^d) with one
+// aggregated proof, where d is the bit length of b-a
+func (params *Params) ProveInterval(v uint64, gamma *big.Int, a uint64, b uint64) (Statement, Proof, error) {
+	if a > b {
+		return Statement{}, Proof{}, errors.New("the lower bound should not exceed the upper bound")
+	}
+	if v < a || v > b {
+		return Statement{}, Proof{}, errors.New("the value should lie in the interval")
+	}
+	if !utils.Is_Valid_Scalar(gamma) {
+		return Statement{}, Proof{}, errors.New("the blinding factor should be an element of Zp")
+	}
+	d := intervalWidth(a, b)
+	if params.Capacity() < 2*d {
+		return Statement{}, Proof{}, errors.New("the generator vectors are shorter than twice the interval width")
+	}
+	b_0 := append(Generate_Value_Bits(v-a, d), Generate_Value_Bits(b-v, d)...)
+	bounds := []*big.Int{new(big.Int).SetUint64(a), new(big.Int).SetUint64(b)}
+	statement, proof := params.proveBits(b_0, []*big.Int{gamma, utils.Neg_Zp(gamma)}, d, bounds, nil)
+	return statement, proof, nil
+}
+
+// Build the statement of an interval proof for the commitment V and the interval [a, b]
+func (params *Params) IntervalStatement(V utils.Point, a uint64, b uint64) (Statement, error) {
+	if a > b {
+		return Statement{}, errors.New("the lower bound should not exceed the upper bound")
+	}
+	bound_a := new(big.Int).SetUint64(a)
+	bound_b := new(big.Int).SetUint64(b)
+	minus_one := utils.Neg_Zp(big.NewInt(1))
+	V_a := utils.Multi_Scalar_Mult([]utils.Point{V, params.Gen_g}, []*big.Int{big.NewInt(1), utils.Neg_Zp(bound_a)})
+	V_b := utils.Multi_Scalar_Mult([]utils.Point{V, params.Gen_g}, []*big.Int{minus_one, bound_b})
+	statement := params.Statement([]utils.Point{V_a, V_b}, intervalWidth(a, b))
+	statement.Bounds = []*big.Int{bound_a, bound_b}
+	return statement, nil
+}
+
+// Verify that the value committed in V lies in [a, b]
+func (params *Params) VerifyInterval(V utils.Point, a uint64, b uint64, proof Proof) error {
+	statement, err := params.IntervalStatement(V, a, b)
+	if err != nil {
+		return err
+	}
+	if err := Verify(statement, proof); err != nil {
+		return errors.New("invalid interval proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// Bit width d of the interval [a, b], the smallest d with b-a < 2^d
+func intervalWidth(a uint64, b uint64) int {
+	d := bits.Len64(b - a)
+	if d == 0 {
+		d = 1
+	}
+	return d
+}
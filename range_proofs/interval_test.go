@@ -0,0 +1,65 @@
+package range_proofs
+
+import (
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+func TestProveInterval(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	var params Params
+	params.Setup(64, 2)
+	tests := []struct {
+		v, a, b uint64
+	}{
+		{1000, 1000, 50000},
+		{50000, 1000, 50000},
+		{18, 18, 1<<64 - 1},
+		{7, 7, 7},
+		{0, 0, 1},
+		{1<<64 - 1, 1 << 63, 1<<64 - 1},
+	}
+	for _, test := range tests {
+		gamma := utils.Generate_Random_Zp(256)
+		_, proof, err := params.ProveInterval(test.v, gamma, test.a, test.b)
+		if err != nil {
+			t.Fatalf("%d in [%d, %d]: %v", test.v, test.a, test.b, err)
+		}
+		V := params.Commit(test.v, gamma)
+		if err := params.VerifyInterval(V, test.a, test.b, proof); err != nil {
+			t.Errorf("%d in [%d, %d]: honest proof rejected: %v", test.v, test.a, test.b, err)
+		}
+
+		// The proof does not carry over to a shifted interval or another commitment
+		if test.b < 1<<64-1 && params.VerifyInterval(V, test.a+1, test.b+1, proof) == nil {
+			t.Errorf("%d in [%d, %d]: proof accepted for the shifted interval", test.v, test.a, test.b)
+		}
+		if params.VerifyInterval(utils.Cal_Point_Add(V, params.Gen_g), test.a, test.b, proof) == nil {
+			t.Errorf("%d in [%d, %d]: proof accepted for v+1", test.v, test.a, test.b)
+		}
+	}
+}
+
+// Values just outside the interval, including b+1, cannot be proven
+func TestProveIntervalOutside(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	var params Params
+	params.Setup(16, 2)
+	tests := []struct {
+		name    string
+		v, a, b uint64
+	}{
+		{"b+1", 50001, 1000, 50000},
+		{"a-1", 999, 1000, 50000},
+		{"empty interval", 5, 6, 5},
+		{"too wide", 0, 0, 1 << 20},
+	}
+	for _, test := range tests {
+		if _, _, err := params.ProveInterval(test.v, utils.Generate_Random_Zp(256), test.a, test.b); err == nil {
+			t.Errorf("%s: proof generated", test.name)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package range_proofs
+
+import (
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Copy of a point vector with the i-th point replaced by a fresh one
+func replacePoint(points []utils.Point, i int) []utils.Point {
+	res := append([]utils.Point{}, points...)
+	res[i] = utils.GeneratePoint()
+	return res
+}
+
+// Changing any field of the statement changes the deterministic nonces of the same witness
+func TestNonceBindsStatement(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	var base Prover
+	base.New(utils.GeneratePoint(), utils.GeneratePoint(), utils.GenerateMultiPoint(16), utils.GenerateMultiPoint(16), 16)
+	first := func(prover Prover) string {
+		prover.Deterministic([]byte("aux"))
+		prover.bindNonces()
+		return prover.s_0[0].String()
+	}
+	nonce := first(base)
+	if first(base) != nonce {
+		t.Fatal("the nonces are not deterministic")
+	}
+
+	mutations := map[string]func(prover *Prover){
+		"g": func(prover *Prover) { prover.Gen_g = utils.GeneratePoint() },
+		"h": func(prover *Prover) { prover.Gen_h = utils.GeneratePoint() },
+		"G": func(prover *Prover) { prover.Gen_Vec_G = replacePoint(prover.Gen_Vec_G, 15) },
+		"H": func(prover *Prover) { prover.Gen_Vec_H = replacePoint(prover.Gen_Vec_H, 0) },
+		"V": func(prover *Prover) { prover.Pub_Coin = utils.GeneratePoint() },
+		"d": func(prover *Prover) { prover.D++ },
+	}
+	for field, mutate := range mutations {
+		prover := base
+		mutate(&prover)
+		if first(prover) == nonce {
+			t.Errorf("the nonces do not depend on %s", field)
+		}
+	}
+}
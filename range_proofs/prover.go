@@ -33,6 +33,9 @@ type Prover struct {
 	beta  *big.Int
 	gamma *big.Int
 
+	// source of the nonces, crypto/rand if nil
+	nonces *utils.Nonce_Source
+
 	// intemediate values zeta, eta, t1, t2 and corresponding randomness
 	t_1, t_2 *big.Int
 	tau_1    *big.Int
@@ -54,12 +57,13 @@ type Prover struct {
 	vec_2N []*big.Int
 }
 
-// Initialization function
+// Initialization function, the prover samples its own witness (value and blinding) at random, only the
+// nonces are derived by Deterministic
 func (prover *Prover) New(G utils.Point, H utils.Point, G_Vector []utils.Point, H_Vector []utils.Point, d int) {
 	prover.Gen_g = G
 	prover.Gen_h = H
-	prover.Gen_Vec_G = G_Vector
-	prover.Gen_Vec_H = H_Vector
+	prover.Gen_Vec_G = G_Vector[:d]
+	prover.Gen_Vec_H = H_Vector[:d]
 	prover.D = d
 	//Generate secrets of prover
 	prover.sec_value = utils.Generate_Random_Zp(d)
@@ -68,29 +72,58 @@ func (prover *Prover) New(G utils.Point, H utils.Point, G_Vector []utils.Point,
 
 ////////////////////////Public interfaces
 
+//Derive the nonces deterministically from the secrets, the statement and the optional extra randomness aux
+func (prover *Prover) Deterministic(aux []byte) {
+	prover.nonces = utils.New_Nonce_Source(aux)
+}
+
 //Get response zeta, eta, t, tau_x, mu, f_s
 func (prover *Prover) GenerateRsp() (utils.Point, Transcript) {
+	prover.bindNonces()
+
+	statement := prover.statement()
+	fs := statement.newTranscript()
 
 	//prover computes Commitments A, B
-	prover.calculateAB()
+	A, B := prover.CommitAB()
+
+	//prover generates challenges y,z and computes Commitments T1, T2
+	fs.Append_Point("A", A)
+	fs.Append_Point("B", B)
+	y := fs.Challenge("y")
+	z := fs.Challenge("z")
+	T1, T2 := prover.CommitT(y, z)
+
+	//prover generates challenge x and computes the responses
+	fs.Append_Point("T1", T1)
+	fs.Append_Point("T2", T2)
+	transcript := prover.Respond(fs.Challenge("x"))
+	return prover.Pub_Coin, transcript
+}
 
-	//prover generates challenges y,z
-	prover.y, prover.z = Generate_YZ(prover.A, prover.B)
+//Round 1: compute the commitments A, B
+func (prover *Prover) CommitAB() (utils.Point, utils.Point) {
+	prover.calculateAB()
+	return prover.A, prover.B
+}
 
-	//prover compute Commitments T1, T2
+//Round 2: compute the commitments T1, T2 for the challenges y, z
+func (prover *Prover) CommitT(y *big.Int, z *big.Int) (utils.Point, utils.Point) {
+	prover.y, prover.z = y, z
 	prover.calculateT()
+	return prover.T1, prover.T2
+}
 
-	//verifier get T1, T2, E and transmit x to prover
-	prover.x = Generate_X(prover.T1, prover.T2)
-
+//Round 3: compute the responses for the challenge x, the prover may be rewound by calling it again with another x
+func (prover *Prover) Respond(x *big.Int) Transcript {
+	prover.x = x
 	prover.calculateLx()
 	prover.calculateRx()
 	prover.calculateIP()
 	prover.calculateMu()
 	prover.calculateTaux()
 
-	//prover.TestCorrect()
-	transcript := Transcript{
+	return Transcript{
 		A:     prover.A,
 		B:     prover.B,
 		T1:    prover.T1,
@@ -106,7 +139,13 @@ func (prover *Prover) GenerateRsp() (utils.Point, Transcript) {
 		Y:     prover.y,
 		Z:     prover.z,
 	}
-	return prover.Pub_Coin, transcript
+}
+
+//Get a non-interactive proof that the committed value lies in [0, 2^D)
+func (prover *Prover) GenerateProof() (Statement, Proof) {
+	var params Params
+	params.New(prover.Gen_g, prover.Gen_h, prover.Gen_Vec_G, prover.Gen_Vec_H)
+	return params.proveBits(prover.b_0, []*big.Int{prover.gamma}, prover.D, nil, prover.nonces)
 }
 
 ////////////////////////Private functions
@@ -114,7 +153,7 @@ func (prover *Prover) GenerateRsp() (utils.Point, Transcript) {
 //Generate b_0,b_1,s_0,s_1 and public keys
 func (prover *Prover) generateCoin() {
 	//Generate binary vector b_0 b_1
-	prover.b_0 = Generate_b_0(prover.D)
+	prover.b_0 = Generate_b_0(prover.sec_value, prover.D)
 	prover.b_1 = Generate_b_1(prover.b_0)
 	prover.s_0 = utils.Generate_Random_Zp_Vector(prover.D, q)
 	prover.s_1 = utils.Generate_Random_Zp_Vector(prover.D, q)
@@ -123,16 +162,39 @@ func (prover *Prover) generateCoin() {
 	prover.Pub_Coin = Generate_Public_Coin(prover.Gen_g, prover.Gen_h, prover.D, prover.b_0, prover.gamma) //generate public key vector Y
 }
 
+//Public statement of the prover
+func (prover *Prover) statement() Statement {
+	return Statement{
+		Gen_g:        prover.Gen_g,
+		Gen_h:        prover.Gen_h,
+		Gen_Vec_G:    prover.Gen_Vec_G,
+		Gen_Vec_H:    prover.Gen_Vec_H,
+		Pub_Vec_Coin: []utils.Point{prover.Pub_Coin},
+		D:            prover.D,
+	}
+}
+
+//Restart the nonces from the secrets and the statement and rederive s_0, s_1
+func (prover *Prover) bindNonces() {
+	if prover.nonces == nil {
+		return
+	}
+	statement := prover.statement()
+	prover.nonces.Bind(*statement.newTranscript(), append(append([]*big.Int{}, prover.b_0...), prover.gamma))
+	prover.s_0 = prover.nonces.Scalar_Vector(prover.D)
+	prover.s_1 = prover.nonces.Scalar_Vector(prover.D)
+}
+
 //Generate Commitments A,B,C,D
 func (prover *Prover) calculateAB() {
 	//Generate commitment A
-	prover.alpha = utils.Generate_Random_Zp(q)
+	prover.alpha = prover.nonces.Scalar()
 	g_b0_h_b1 := utils.Pedersen_Commit_Vector(prover.Gen_Vec_G, prover.Gen_Vec_H, prover.b_0, prover.b_1)
 	h_alpha := utils.Commit(prover.Gen_h, prover.alpha)
 	prover.A.X, prover.A.Y = utils.Curve.Add(g_b0_h_b1.X, g_b0_h_b1.Y, h_alpha.X, h_alpha.Y)
 
 	//Generate commitment B
-	prover.beta = utils.Generate_Random_Zp(q)
+	prover.beta = prover.nonces.Scalar()
 	g_s0_h_s1 := utils.Pedersen_Commit_Vector(prover.Gen_Vec_G, prover.Gen_Vec_H, prover.s_0, prover.s_1)
 	h_beta := utils.Commit(prover.Gen_h, prover.beta)
 	prover.B.X, prover.B.Y = utils.Curve.Add(g_s0_h_s1.X, g_s0_h_s1.Y, h_beta.X, h_beta.Y)
@@ -162,7 +224,7 @@ func (prover *Prover) calculateT() {
 	prover.t_1 = utils.Add_In_P(prover.t_1, t13)
 
 	//Generate tau1
-	prover.tau_1 = utils.Generate_Random_Zp(q)
+	prover.tau_1 = prover.nonces.Scalar()
 
 	//Compute T1
 	prover.T1 = utils.Pedersen_Commit(prover.Gen_g, prover.Gen_h, prover.t_1, prover.tau_1)
@@ -171,17 +233,17 @@ func (prover *Prover) calculateT() {
 	prover.t_2 = utils.Cal_IP_Vec(s0_yN, prover.s_1)
 
 	//Generate tau2
-	prover.tau_2 = utils.Generate_Random_Zp(q)
+	prover.tau_2 = prover.nonces.Scalar()
 
 	//Compute T2
 	prover.T2 = utils.Pedersen_Commit(prover.Gen_g, prover.Gen_h, prover.t_2, prover.tau_2)
 }
 
-//Compute l(x) i.e., zeta = b0 - z1^N + s0x
+//Compute l(x) i.e., zeta = b0 + z1^N + s0x
 func (prover *Prover) calculateLx() {
 	var lx []*big.Int
 	z_1N := Generate_Scalar_Vector(prover.z, prover.D)
-	b0_z_1N := utils.Cal_Sub_Vec(prover.b_0, z_1N)
+	b0_z_1N := utils.Cal_Add_Vec(prover.b_0, z_1N)
 	s0_x := utils.Cal_Sca_Vec(prover.s_0, prover.x)
 
 	lx = utils.Cal_Add_Vec(b0_z_1N, s0_x)
@@ -0,0 +1,45 @@
+package range_proofs
+
+import (
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// The round-based prover draws its challenges from the transcript of its statement
+func TestGenerateRspChallenges(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	var prover Prover
+	prover.New(utils.GeneratePoint(), utils.GeneratePoint(), utils.GenerateMultiPoint(16), utils.GenerateMultiPoint(16), 16)
+	V, trans := prover.GenerateRsp()
+	statement := prover.statement()
+	if !utils.Is_Equal_Point(V, statement.Pub_Vec_Coin[0]) {
+		t.Fatal("the transcript is not for the prover's commitment")
+	}
+	if err := CheckTranscript(statement, trans); err != nil {
+		t.Fatalf("honest transcript rejected: %v", err)
+	}
+
+	fs := statement.newTranscript()
+	fs.Append_Point("A", trans.A)
+	fs.Append_Point("B", trans.B)
+	y, z := fs.Challenge("y"), fs.Challenge("z")
+	fs.Append_Point("T1", trans.T1)
+	fs.Append_Point("T2", trans.T2)
+	x := fs.Challenge("x")
+	if y.Cmp(trans.Y) != 0 || z.Cmp(trans.Z) != 0 || x.Cmp(trans.X) != 0 {
+		t.Fatal("the challenges are not derived from the statement transcript")
+	}
+
+	// The same commitments give other challenges for another statement
+	other := statement
+	other.Gen_Vec_G = replacePoint(other.Gen_Vec_G, 3)
+	fs = other.newTranscript()
+	fs.Append_Point("A", trans.A)
+	fs.Append_Point("B", trans.B)
+	if fs.Challenge("y").Cmp(trans.Y) == 0 {
+		t.Fatal("the challenge y does not depend on the generators")
+	}
+}
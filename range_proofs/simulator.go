@@ -0,0 +1,150 @@
+package range_proofs
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Verifier challenges of an interactive range proof
+type Challenges struct {
+	Y, Z, X *big.Int
+}
+
+// Simulate an interactive transcript for the given challenges without the values or blindings. The responses
+// zeta, eta, tau_x, mu and the commitments A, T2 are uniform as in a real transcript, and B, T1 are the unique
+// values that satisfy the verification equations, so simulated and real transcripts have the same distribution
+func Simulate(statement Statement, challenges Challenges) Transcript {
+	d := statement.D
+	m := len(statement.Pub_Vec_Coin)
+	nm := d * m
+	y, z, x := challenges.Y, challenges.Z, challenges.X
+	inv_x := utils.Inverse_Zp(x)
+
+	zeta := randomVector(nm)
+	eta := randomVector(nm)
+	tau_x := randomScalar()
+	mu := randomScalar()
+	A := utils.Commit(statement.Gen_h, randomScalar())
+	T2 := utils.Commit(statement.Gen_h, randomScalar())
+	ip := utils.Cal_IP_Vec(zeta, eta)
+
+	// T1 = (g^{ip - delta} h^{tau_x} \prod_j V_j^{-z^{j+2}} T2^{-x^2})^{1/x}
+	points := []utils.Point{statement.Gen_g, statement.Gen_h, T2}
+	scalars := []*big.Int{utils.Sub_In_P(ip, transcriptDelta(y, z, d, m)), tau_x, utils.Neg_Zp(utils.Mul_In_P(x, x))}
+	zj := utils.Mul_In_P(z, z)
+	for j := 0; j < m; j++ {
+		points = append(points, statement.Pub_Vec_Coin[j])
+		scalars = append(scalars, utils.Neg_Zp(zj))
+		zj = utils.Mul_In_P(zj, z)
+	}
+	T1 := utils.Cal_Point_Sca(utils.Multi_Scalar_Mult(points, scalars), inv_x)
+
+	// B = (g^{zeta - z} (h^{y^{-nm}})^{eta - z y^{nm} - \sum_j z^{j+2} 2^d_j} h^{mu} A^{-1})^{1/x}
+	points = append(append([]utils.Point{}, statement.Gen_Vec_G[:nm]...), statement.Gen_Vec_H[:nm]...)
+	scalars = append(utils.Cal_Sub_Vec(zeta, Generate_Scalar_Vector(z, nm)), openingExponents(eta, y, z, d, m)...)
+	points = append(points, statement.Gen_h, A)
+	scalars = append(scalars, mu, utils.Neg_Zp(big.NewInt(1)))
+	B := utils.Cal_Point_Sca(utils.Multi_Scalar_Mult(points, scalars), inv_x)
+
+	return Transcript{
+		A:     A,
+		B:     B,
+		T1:    T1,
+		T2:    T2,
+		Tau_x: tau_x,
+		Mu:    mu,
+		Ip:    ip,
+		Zeta:  zeta,
+		Eta:   eta,
+		X:     x,
+		Y:     y,
+		Z:     z,
+	}
+}
+
+// Check an interactive transcript with uncompressed openings against the statement:
+// (1) g^{ip} h^{tau_x} = \prod_j V_j^{z^{j+2}} g^{delta} T1^x T2^{x^2} with ip = <zeta, eta>
+// (2) g^{zeta} (h^{y^{-nm}})^{eta} = A B^x g^z (h^{y^{-nm}})^{z y^{nm} + \sum_j z^{j+2} 2^d_j} h^{-mu}
+func CheckTranscript(statement Statement, trans Transcript) error {
+	d := statement.D
+	m := len(statement.Pub_Vec_Coin)
+	nm := d * m
+	if nm <= 0 || len(statement.Gen_Vec_G) < nm || len(statement.Gen_Vec_H) < nm || len(trans.Zeta) != nm || len(trans.Eta) != nm {
+		return errors.New("the openings should be as long as the value width times the number of values")
+	}
+	for _, c := range []*big.Int{trans.X, trans.Y, trans.Z} {
+		if !utils.Is_Valid_Scalar(c) || c.Sign() == 0 {
+			return errors.New("the challenges should be non-zero elements of Zp")
+		}
+	}
+	y, z, x := trans.Y, trans.Z, trans.X
+	if utils.Cal_IP_Vec(trans.Zeta, trans.Eta).Cmp(trans.Ip) != 0 {
+		return errors.New("the inner product does not match the openings")
+	}
+
+	var me utils.Multi_Exp
+	me.Add_Term(statement.Gen_g, utils.Sub_In_P(trans.Ip, transcriptDelta(y, z, d, m)))
+	me.Add_Term(statement.Gen_h, trans.Tau_x)
+	zj := utils.Mul_In_P(z, z)
+	for j := 0; j < m; j++ {
+		me.Add_Term(statement.Pub_Vec_Coin[j], utils.Neg_Zp(zj))
+		zj = utils.Mul_In_P(zj, z)
+	}
+	me.Add_Term(trans.T1, utils.Neg_Zp(x))
+	me.Add_Term(trans.T2, utils.Neg_Zp(utils.Mul_In_P(x, x)))
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("the transcript fails the inner-product equation")
+	}
+
+	me = utils.Multi_Exp{}
+	me.Add_Terms(statement.Gen_Vec_G[:nm], utils.Cal_Sub_Vec(trans.Zeta, Generate_Scalar_Vector(z, nm)))
+	me.Add_Terms(statement.Gen_Vec_H[:nm], openingExponents(trans.Eta, y, z, d, m))
+	me.Add_Term(statement.Gen_h, trans.Mu)
+	me.Add_Term(trans.A, utils.Neg_Zp(big.NewInt(1)))
+	me.Add_Term(trans.B, utils.Neg_Zp(x))
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("the transcript fails the opening equation")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// delta = (z+z^2) <1^{nm}, y^{nm}> + \sum_j z^{j+3} <1^d, 2^d>
+func transcriptDelta(y *big.Int, z *big.Int, d int, m int) *big.Int {
+	nm := d * m
+	z2 := utils.Mul_In_P(z, z)
+	delta := utils.Mul_In_P(utils.Add_In_P(z, z2), utils.Cal_IP_Vec(Generate_Scalar_Vector(big.NewInt(1), nm), Generate_Exp_Scalar_Vector(y, nm)))
+	sum_2N := utils.Cal_IP_Vec(Generate_Scalar_Vector(big.NewInt(1), d), Generate_Exp_Scalar_Vector(big.NewInt(2), d))
+	zj := utils.Mul_In_P(z2, z)
+	for j := 0; j < m; j++ {
+		delta = utils.Add_In_P(delta, utils.Mul_In_P(zj, sum_2N))
+		zj = utils.Mul_In_P(zj, z)
+	}
+	return delta
+}
+
+// Exponents y^{-i} (eta_i - z y^i - z2N_i) of h_i in the opening equation
+func openingExponents(eta []*big.Int, y *big.Int, z *big.Int, d int, m int) []*big.Int {
+	nm := d * m
+	yN := Generate_Exp_Scalar_Vector(y, nm)
+	Inv_yN := Generate_Exp_Scalar_Vector(utils.Inverse_Zp(y), nm)
+	shift := utils.Cal_Add_Vec(utils.Cal_Sca_Vec(yN, z), Generate_Aggregate_Vector(z, d, m))
+	return utils.Cal_HP_Vec(Inv_yN, utils.Cal_Sub_Vec(eta, shift))
+}
+
+// Draw a uniform element of Zp
+func randomScalar() *big.Int {
+	return utils.Add_In_P(utils.Generate_Random_Zp(q), big.NewInt(0))
+}
+
+// Draw n uniform elements of Zp
+func randomVector(n int) []*big.Int {
+	var res []*big.Int
+	for i := 0; i < n; i++ {
+		res = append(res, randomScalar())
+	}
+	return res
+}
@@ -40,8 +40,8 @@ type Verifier struct {
 func (verifier *Verifier) New(G utils.Point, H utils.Point, G_Vector []utils.Point, H_Vector []utils.Point, d int) {
 	verifier.Gen_g = G
 	verifier.Gen_h = H
-	verifier.Gen_Vec_G = G_Vector
-	verifier.Gen_Vec_H = H_Vector
+	verifier.Gen_Vec_G = G_Vector[:d]
+	verifier.Gen_Vec_H = H_Vector[:d]
 	verifier.d = d
 }
 
@@ -71,14 +71,13 @@ func (verifier *Verifier) Validate() utils.Point {
 	// Compute Right hand side
 	var RHS utils.Point
 	// Compute the part in Step (1)
-	// Compute delta = <z \cdot 1^N \circ y^N, (z+1) \cdot 1^N>
+	// Compute delta = (z+z^2) <1^N, y^N> + z^3 <1^N, 2^N>
 	z2 := utils.Mul_In_P(verifier.z, verifier.z)
 	Gen_V_z2 := utils.Commit(verifier.Pub_Coin, z2)
 
 	v1N := Generate_Scalar_Vector(big.NewInt(1), verifier.d)
-	verifier.yN = Generate_Scalar_Vector(verifier.y, verifier.d)
 
-	z_z2 := utils.Sub_In_P(verifier.z, z2)
+	z_z2 := utils.Add_In_P(verifier.z, z2)
 	v1N_yN := utils.Cal_IP_Vec(v1N, verifier.yN)
 	delta_1 := utils.Mul_In_P(z_z2, v1N_yN)
 
@@ -86,7 +85,7 @@ func (verifier *Verifier) Validate() utils.Point {
 	v1N_2N := utils.Cal_IP_Vec(v1N, verifier.vec_2N)
 	delta_2 := utils.Mul_In_P(z3, v1N_2N)
 
-	delta := utils.Sub_In_P(delta_1, delta_2)
+	delta := utils.Add_In_P(delta_1, delta_2)
 	Gen_g_delta := utils.Commit(verifier.Gen_g, delta)
 
 	x2 := utils.Mul_In_P(verifier.x, verifier.x)
@@ -105,8 +104,8 @@ func (verifier *Verifier) Validate() utils.Point {
 	Inv_Vec_H := utils.Generate_Point_Vector_with_y(verifier.Gen_Vec_H, Inv_yN)
 
 	A_Bx := utils.Pedersen_Commit(verifier.A, verifier.B, big.NewInt(1), verifier.x)
-	neg_z1N := Generate_Scalar_Vector(utils.Neg_Zp(verifier.z), verifier.d)
-	g_neg_z1N := utils.Commit_Vector(verifier.Gen_Vec_G, neg_z1N)
+	z1N := Generate_Scalar_Vector(verifier.z, verifier.d)
+	g_z1N := utils.Commit_Vector(verifier.Gen_Vec_G, z1N)
 
 	z_yN := utils.Cal_Sca_Vec(verifier.yN, verifier.z)
 	z2_2N := utils.Cal_Sca_Vec(verifier.vec_2N, z2)
@@ -115,10 +114,10 @@ func (verifier *Verifier) Validate() utils.Point {
 	Gen_h_zy := utils.Commit_Vector(Inv_Vec_H, z_yN_z2_2N)
 
 	RHS = utils.Cal_Point_Add(RHS, A_Bx)
-	RHS = utils.Cal_Point_Add(RHS, g_neg_z1N)
+	RHS = utils.Cal_Point_Add(RHS, g_z1N)
 	RHS = utils.Cal_Point_Add(RHS, Gen_h_zy)
 
-	Com_mu := utils.Commit(verifier.Gen_h, verifier.mu)
+	Com_mu := utils.Commit(verifier.Gen_h, utils.Neg_Zp(verifier.mu))
 	RHS = utils.Cal_Point_Add(RHS, Com_mu)
 
 	return RHS
@@ -0,0 +1,92 @@
+package sigma
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Proof that (R, D) = (g^rho, v^b h^rho) is an ElGamal encryption of a bit b, the OR of the Chaum-Pedersen
+// proofs of log_g R = log_h D (b = 0) and log_g R = log_h (D v^{-1}) (b = 1)
+type BitProof struct {
+	T_R0, T_D0 utils.Point // commitments of branch 0, T_R0 = g^{a_0}, T_D0 = h^{a_0} for the true branch
+	T_R1, T_D1 utils.Point // commitments of branch 1
+	C_0        *big.Int    // challenge of branch 0, branch 1 answers c_1 = c - c_0
+	S_0, S_1   *big.Int    // responses s_j = a_j + c_j rho
+}
+
+// Prove that (R, D) encrypts the bit b under the key h with randomness rho
+func ProveBit(fs *utils.Fiat_Shamir, g utils.Point, h utils.Point, v utils.Point, R utils.Point, D utils.Point, b *big.Int, rho *big.Int) BitProof {
+	bit := int(b.Int64())
+	T_R := make([]utils.Point, 2)
+	T_D := make([]utils.Point, 2)
+	c := make([]*big.Int, 2)
+	s := make([]*big.Int, 2)
+
+	// Simulate the false branch for a random challenge and response
+	nonces := utils.Hedged_Nonce_Source(*fs, []*big.Int{b, rho})
+	fake := 1 - bit
+	c[fake] = nonces.Scalar()
+	s[fake] = nonces.Scalar()
+	T_R[fake], T_D[fake] = simulateBranch(g, h, v, R, D, fake, c[fake], s[fake])
+
+	// Commit honestly on the true branch
+	a := nonces.Scalar()
+	T_R[bit] = utils.Commit(g, a)
+	T_D[bit] = utils.Commit(h, a)
+
+	absorbBit(fs, []utils.Point{g, h, v, R, D}, T_R, T_D)
+	c[bit] = utils.Sub_In_P(fs.Challenge("c"), c[fake])
+	s[bit] = utils.Add_In_P(a, utils.Mul_In_P(c[bit], rho))
+
+	return BitProof{T_R0: T_R[0], T_D0: T_D[0], T_R1: T_R[1], T_D1: T_D[1], C_0: c[0], S_0: s[0], S_1: s[1]}
+}
+
+// Verify that (R, D) encrypts a bit, fs should be in the same state as the prover's transcript
+func VerifyBit(fs *utils.Fiat_Shamir, g utils.Point, h utils.Point, v utils.Point, R utils.Point, D utils.Point, proof BitProof) error {
+	if !validPoints(g, h, v, R, D, proof.T_R0, proof.T_D0, proof.T_R1, proof.T_D1) || !validScalars(proof.C_0, proof.S_0, proof.S_1) {
+		return errors.New("the bit proof is malformed")
+	}
+	T_R := []utils.Point{proof.T_R0, proof.T_R1}
+	T_D := []utils.Point{proof.T_D0, proof.T_D1}
+	absorbBit(fs, []utils.Point{g, h, v, R, D}, T_R, T_D)
+	c := []*big.Int{proof.C_0, utils.Sub_In_P(fs.Challenge("c"), proof.C_0)}
+	s := []*big.Int{proof.S_0, proof.S_1}
+	neg_1 := utils.Neg_Zp(big.NewInt(1))
+
+	// g^{s_j} R^{-c_j} T_{R,j}^{-1} = 1 and h^{s_j} (D v^{-j})^{-c_j} T_{D,j}^{-1} = 1 for j = 0,1,
+	// combined with random weights
+	var me utils.Multi_Exp
+	for j := 0; j < 2; j++ {
+		w_R := utils.Generate_Random_Zp(q)
+		w_D := utils.Generate_Random_Zp(q)
+		neg_c := utils.Neg_Zp(c[j])
+		me.Add_Terms([]utils.Point{g, R, T_R[j]}, []*big.Int{utils.Mul_In_P(w_R, s[j]), utils.Mul_In_P(w_R, neg_c), utils.Mul_In_P(w_R, neg_1)})
+		me.Add_Terms([]utils.Point{h, D, T_D[j]}, []*big.Int{utils.Mul_In_P(w_D, s[j]), utils.Mul_In_P(w_D, neg_c), utils.Mul_In_P(w_D, neg_1)})
+		if j == 1 {
+			me.Add_Term(v, utils.Mul_In_P(w_D, c[j]))
+		}
+	}
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("invalid bit proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+// Commitments T_R = g^s R^{-c}, T_D = h^s (D v^{-j})^{-c} of a simulated branch j
+func simulateBranch(g utils.Point, h utils.Point, v utils.Point, R utils.Point, D utils.Point, j int, c *big.Int, s *big.Int) (utils.Point, utils.Point) {
+	neg_c := utils.Neg_Zp(c)
+	T_R := utils.Multi_Scalar_Mult([]utils.Point{g, R}, []*big.Int{s, neg_c})
+	T_D := utils.Multi_Scalar_Mult([]utils.Point{h, D, v}, []*big.Int{s, neg_c, utils.Mul_In_P(c, big.NewInt(int64(j)))})
+	return T_R, T_D
+}
+
+func absorbBit(fs *utils.Fiat_Shamir, statement []utils.Point, T_R []utils.Point, T_D []utils.Point) {
+	fs.Append_Bytes("protocol", []byte("sigma/bit"))
+	fs.Append_Points("statement", statement)
+	fs.Append_Points("T_R", T_R)
+	fs.Append_Points("T_D", T_D)
+}
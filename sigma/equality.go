@@ -0,0 +1,69 @@
+package sigma
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Proof that C_1 = g_1^v h_1^{r_1} and C_2 = g_2^v h_2^{r_2} commit to the same value v
+type EqualityProof struct {
+	T1, T2          utils.Point // commitments T_1 = g_1^a h_1^{b_1}, T_2 = g_2^a h_2^{b_2}
+	S_v, S_r1, S_r2 *big.Int    // responses s_v = a + c v, s_{r_j} = b_j + c r_j
+}
+
+// Prove that C_1 under (g_1, h_1) and C_2 under (g_2, h_2) commit to the same value v
+func ProveEquality(fs *utils.Fiat_Shamir, g1 utils.Point, h1 utils.Point, C1 utils.Point, r1 *big.Int,
+	g2 utils.Point, h2 utils.Point, C2 utils.Point, r2 *big.Int, v *big.Int) EqualityProof {
+	nonces := utils.Hedged_Nonce_Source(*fs, []*big.Int{v, r1, r2})
+	a := nonces.Scalar()
+	b_1 := nonces.Scalar()
+	b_2 := nonces.Scalar()
+	T1 := utils.Pedersen_Commit(g1, h1, a, b_1)
+	T2 := utils.Pedersen_Commit(g2, h2, a, b_2)
+
+	absorbEquality(fs, []utils.Point{g1, h1, C1, g2, h2, C2}, T1, T2)
+	c := fs.Challenge("c")
+
+	return EqualityProof{
+		T1:   T1,
+		T2:   T2,
+		S_v:  utils.Add_In_P(a, utils.Mul_In_P(c, v)),
+		S_r1: utils.Add_In_P(b_1, utils.Mul_In_P(c, r1)),
+		S_r2: utils.Add_In_P(b_2, utils.Mul_In_P(c, r2)),
+	}
+}
+
+// Verify that C_1 and C_2 commit to the same value, fs should be in the same state as the prover's transcript
+func VerifyEquality(fs *utils.Fiat_Shamir, g1 utils.Point, h1 utils.Point, C1 utils.Point,
+	g2 utils.Point, h2 utils.Point, C2 utils.Point, proof EqualityProof) error {
+	if !validPoints(g1, h1, C1, g2, h2, C2, proof.T1, proof.T2) || !validScalars(proof.S_v, proof.S_r1, proof.S_r2) {
+		return errors.New("the equality proof is malformed")
+	}
+	absorbEquality(fs, []utils.Point{g1, h1, C1, g2, h2, C2}, proof.T1, proof.T2)
+	c := fs.Challenge("c")
+	neg_c := utils.Neg_Zp(c)
+	neg_1 := utils.Neg_Zp(big.NewInt(1))
+
+	// g_j^{s_v} h_j^{s_{r_j}} C_j^{-c} T_j^{-1} = 1 for j = 1,2, combined with a random weight
+	w := utils.Generate_Random_Zp(q)
+	points := []utils.Point{g1, h1, C1, proof.T1, g2, h2, C2, proof.T2}
+	scalars := []*big.Int{proof.S_v, proof.S_r1, neg_c, neg_1,
+		utils.Mul_In_P(w, proof.S_v), utils.Mul_In_P(w, proof.S_r2), utils.Mul_In_P(w, neg_c), utils.Mul_In_P(w, neg_1)}
+	var me utils.Multi_Exp
+	me.Add_Terms(points, scalars)
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("invalid equality proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+func absorbEquality(fs *utils.Fiat_Shamir, statement []utils.Point, T1 utils.Point, T2 utils.Point) {
+	fs.Append_Bytes("protocol", []byte("sigma/equality"))
+	fs.Append_Points("statement", statement)
+	fs.Append_Point("T1", T1)
+	fs.Append_Point("T2", T2)
+}
@@ -0,0 +1,49 @@
+package sigma
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+func TestEquality(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	g1, h1, g2, h2 := utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint(), utils.GeneratePoint()
+	v, r1, r2 := utils.Generate_Random_Zp(256), utils.Generate_Random_Zp(256), utils.Generate_Random_Zp(256)
+	C1 := utils.Pedersen_Commit(g1, h1, v, r1)
+	C2 := utils.Pedersen_Commit(g2, h2, v, r2)
+	one := big.NewInt(1)
+
+	tests := []struct {
+		name   string
+		C2     utils.Point
+		v      *big.Int // value used by the prover for C_2's opening
+		accept bool
+	}{
+		{"equal values", C2, v, true},
+		{"different values", utils.Pedersen_Commit(g2, h2, utils.Add_In_P(v, one), r2), v, false},
+		{"prover claims the other value", utils.Pedersen_Commit(g2, h2, utils.Add_In_P(v, one), r2), utils.Add_In_P(v, one), false},
+	}
+	for _, test := range tests {
+		proof := ProveEquality(testTranscript("a"), g1, h1, C1, r1, g2, h2, test.C2, r2, test.v)
+		err := VerifyEquality(testTranscript("a"), g1, h1, C1, g2, h2, test.C2, proof)
+		if (err == nil) != test.accept {
+			t.Errorf("%s: err = %v", test.name, err)
+		}
+	}
+
+	proof := ProveEquality(testTranscript("a"), g1, h1, C1, r1, g2, h2, C2, r2, v)
+	if VerifyEquality(testTranscript("b"), g1, h1, C1, g2, h2, C2, proof) == nil {
+		t.Error("proof accepted in another context")
+	}
+	if VerifyEquality(testTranscript("a"), g2, h2, C2, g1, h1, C1, proof) == nil {
+		t.Error("proof accepted with the commitments swapped")
+	}
+	proof.S_r2 = utils.Add_In_P(proof.S_r2, one)
+	if VerifyEquality(testTranscript("a"), g1, h1, C1, g2, h2, C2, proof) == nil {
+		t.Error("proof accepted with a wrong response")
+	}
+}
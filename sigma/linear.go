@@ -0,0 +1,75 @@
+package sigma
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+// Proof that \prod_i C_i^{a_i} commits to the public value w under (g, h), i.e., D = \prod_i C_i^{a_i} g^{-w} = h^rho
+type LinearProof struct {
+	T   utils.Point // commitment T = h^b
+	S_r *big.Int    // response s_r = b + c rho
+}
+
+// Prove that \sum_i a_i v_i = w for the commitments C_i = g^{v_i} h^{r_i}, given the blinding factors r_i.
+// With coefficients 1 for inputs and -1 for outputs and w the public fee this proves balance conservation
+func ProveLinear(fs *utils.Fiat_Shamir, g utils.Point, h utils.Point, commitments []utils.Point, coefficients []*big.Int,
+	blindings []*big.Int, w *big.Int) (LinearProof, error) {
+	if len(commitments) == 0 || len(coefficients) != len(commitments) || len(blindings) != len(commitments) {
+		return LinearProof{}, errors.New("the numbers of commitments, coefficients and blinding factors should be equal and positive")
+	}
+	rho := big.NewInt(0)
+	for i := range blindings {
+		rho = utils.Add_In_P(rho, utils.Mul_In_P(coefficients[i], blindings[i]))
+	}
+	b := utils.Hedged_Nonce_Source(*fs, []*big.Int{rho}).Scalar()
+	T := utils.Commit(h, b)
+
+	absorbLinear(fs, g, h, commitments, coefficients, w, T)
+	c := fs.Challenge("c")
+
+	return LinearProof{T: T, S_r: utils.Add_In_P(b, utils.Mul_In_P(c, rho))}, nil
+}
+
+// Verify that \prod_i C_i^{a_i} commits to w, fs should be in the same state as the prover's transcript
+func VerifyLinear(fs *utils.Fiat_Shamir, g utils.Point, h utils.Point, commitments []utils.Point, coefficients []*big.Int,
+	w *big.Int, proof LinearProof) error {
+	if len(commitments) == 0 || len(coefficients) != len(commitments) {
+		return errors.New("the numbers of commitments and coefficients should be equal and positive")
+	}
+	if !validPoints(append([]utils.Point{g, h, proof.T}, commitments...)...) || !validScalars(append([]*big.Int{w, proof.S_r}, coefficients...)...) {
+		return errors.New("the linear relation proof is malformed")
+	}
+	absorbLinear(fs, g, h, commitments, coefficients, w, proof.T)
+	c := fs.Challenge("c")
+	neg_c := utils.Neg_Zp(c)
+
+	// h^{s_r} (\prod_i C_i^{a_i} g^{-w})^{-c} T^{-1} = 1
+	var me utils.Multi_Exp
+	me.Add_Term(h, proof.S_r)
+	me.Add_Term(g, utils.Mul_In_P(c, w))
+	for i := range commitments {
+		me.Add_Term(commitments[i], utils.Mul_In_P(neg_c, coefficients[i]))
+	}
+	me.Add_Term(proof.T, utils.Neg_Zp(big.NewInt(1)))
+	if !utils.Is_Identity_Point(me.Calculate()) {
+		return errors.New("invalid linear relation proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+func absorbLinear(fs *utils.Fiat_Shamir, g utils.Point, h utils.Point, commitments []utils.Point, coefficients []*big.Int, w *big.Int, T utils.Point) {
+	fs.Append_Bytes("protocol", []byte("sigma/linear"))
+	fs.Append_Point("g", g)
+	fs.Append_Point("h", h)
+	fs.Append_Points("C", commitments)
+	for i := range coefficients {
+		fs.Append_Scalar("a", coefficients[i])
+	}
+	fs.Append_Scalar("w", w)
+	fs.Append_Point("T", T)
+}
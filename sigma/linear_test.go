@@ -0,0 +1,55 @@
+package sigma
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Balance proofs sum(inputs) - sum(outputs) = fee
+func TestLinear(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	g, h := utils.GeneratePoint(), utils.GeneratePoint()
+	minus := utils.Neg_Zp(big.NewInt(1))
+	tests := []struct {
+		name   string
+		values []int64
+		signs  []*big.Int
+		w      int64
+		accept bool
+	}{
+		{"balanced", []int64{70, 30, 95}, []*big.Int{big.NewInt(1), big.NewInt(1), minus}, 5, true},
+		{"single commitment", []int64{42}, []*big.Int{big.NewInt(1)}, 42, true},
+		{"weighted", []int64{3, 4}, []*big.Int{big.NewInt(2), big.NewInt(5)}, 26, true},
+		{"fee too low", []int64{70, 30, 95}, []*big.Int{big.NewInt(1), big.NewInt(1), minus}, 4, false},
+		{"inflation", []int64{70, 30, 96}, []*big.Int{big.NewInt(1), big.NewInt(1), minus}, 5, false},
+	}
+	for _, test := range tests {
+		var commitments []utils.Point
+		var blindings []*big.Int
+		for _, value := range test.values {
+			r := utils.Generate_Random_Zp(256)
+			commitments = append(commitments, utils.Pedersen_Commit(g, h, big.NewInt(value), r))
+			blindings = append(blindings, r)
+		}
+		w := big.NewInt(test.w)
+		proof, err := ProveLinear(testTranscript("a"), g, h, commitments, test.signs, blindings, w)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		err = VerifyLinear(testTranscript("a"), g, h, commitments, test.signs, w, proof)
+		if (err == nil) != test.accept {
+			t.Errorf("%s: err = %v", test.name, err)
+		}
+		if test.accept && VerifyLinear(testTranscript("b"), g, h, commitments, test.signs, w, proof) == nil {
+			t.Errorf("%s: proof accepted in another context", test.name)
+		}
+	}
+
+	if _, err := ProveLinear(testTranscript("a"), g, h, []utils.Point{g}, nil, nil, big.NewInt(0)); err == nil {
+		t.Error("proof generated without coefficients")
+	}
+}
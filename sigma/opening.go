@@ -0,0 +1,81 @@
+package sigma
+
+import (
+	"errors"
+	"math/big"
+
+	"anyOutOfMany/utils"
+)
+
+const q = 256
+
+// Proof of knowledge of an opening (v, r) of C = g^v h^r
+type OpeningProof struct {
+	T        utils.Point // commitment T = g^a h^b
+	S_v, S_r *big.Int    // responses s_v = a + c v, s_r = b + c r
+}
+
+// Prove knowledge of (v, r) with C = g^v h^r, the challenge is derived from fs after absorbing the statement,
+// so the proof is bound to everything absorbed into fs before. The nonces of every sigma prover are hedged with
+// the witness and the state of fs
+func ProveOpening(fs *utils.Fiat_Shamir, g utils.Point, h utils.Point, C utils.Point, v *big.Int, r *big.Int) OpeningProof {
+	nonces := utils.Hedged_Nonce_Source(*fs, []*big.Int{v, r})
+	a := nonces.Scalar()
+	b := nonces.Scalar()
+	T := utils.Pedersen_Commit(g, h, a, b)
+
+	absorbOpening(fs, g, h, C, T)
+	c := fs.Challenge("c")
+
+	return OpeningProof{
+		T:   T,
+		S_v: utils.Add_In_P(a, utils.Mul_In_P(c, v)),
+		S_r: utils.Add_In_P(b, utils.Mul_In_P(c, r)),
+	}
+}
+
+// Verify a proof of opening of C, fs should be in the same state as the prover's transcript
+func VerifyOpening(fs *utils.Fiat_Shamir, g utils.Point, h utils.Point, C utils.Point, proof OpeningProof) error {
+	if !validPoints(g, h, C, proof.T) || !validScalars(proof.S_v, proof.S_r) {
+		return errors.New("the opening proof is malformed")
+	}
+	absorbOpening(fs, g, h, C, proof.T)
+	c := fs.Challenge("c")
+
+	// g^{s_v} h^{s_r} C^{-c} T^{-1} = 1
+	res := utils.Multi_Scalar_Mult([]utils.Point{g, h, C, proof.T}, []*big.Int{proof.S_v, proof.S_r, utils.Neg_Zp(c), utils.Neg_Zp(big.NewInt(1))})
+	if !utils.Is_Identity_Point(res) {
+		return errors.New("invalid opening proof")
+	}
+	return nil
+}
+
+////////////////////////Private functions
+
+func absorbOpening(fs *utils.Fiat_Shamir, g utils.Point, h utils.Point, C utils.Point, T utils.Point) {
+	fs.Append_Bytes("protocol", []byte("sigma/opening"))
+	fs.Append_Point("g", g)
+	fs.Append_Point("h", h)
+	fs.Append_Point("C", C)
+	fs.Append_Point("T", T)
+}
+
+// Check that all points are on the curve
+func validPoints(points ...utils.Point) bool {
+	for _, point := range points {
+		if !utils.Is_Valid_Point(point) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check that all scalars lie in Zp
+func validScalars(scalars ...*big.Int) bool {
+	for _, scalar := range scalars {
+		if !utils.Is_Valid_Scalar(scalar) {
+			return false
+		}
+	}
+	return true
+}
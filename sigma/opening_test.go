@@ -0,0 +1,56 @@
+package sigma
+
+import (
+	"math/big"
+	"testing"
+
+	"anyOutOfMany/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Fresh transcript of a test context
+func testTranscript(context string) *utils.Fiat_Shamir {
+	var fs utils.Fiat_Shamir
+	fs.New("sigma/test")
+	fs.Append_Bytes("context", []byte(context))
+	return &fs
+}
+
+func TestOpening(t *testing.T) {
+	utils.Curve = secp256k1.S256()
+	g, h := utils.GeneratePoint(), utils.GeneratePoint()
+	v, r := utils.Generate_Random_Zp(256), utils.Generate_Random_Zp(256)
+	C := utils.Pedersen_Commit(g, h, v, r)
+	proof := ProveOpening(testTranscript("a"), g, h, C, v, r)
+	if err := VerifyOpening(testTranscript("a"), g, h, C, proof); err != nil {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+
+	one := big.NewInt(1)
+	tests := []struct {
+		name    string
+		context string
+		C       utils.Point
+		proof   OpeningProof
+	}{
+		{"another context", "b", C, proof},
+		{"another commitment", "a", utils.Cal_Point_Add(C, g), proof},
+		{"wrong s_v", "a", C, OpeningProof{T: proof.T, S_v: utils.Add_In_P(proof.S_v, one), S_r: proof.S_r}},
+		{"wrong s_r", "a", C, OpeningProof{T: proof.T, S_v: proof.S_v, S_r: utils.Add_In_P(proof.S_r, one)}},
+		{"wrong T", "a", C, OpeningProof{T: utils.Cal_Point_Add(proof.T, h), S_v: proof.S_v, S_r: proof.S_r}},
+		{"missing response", "a", C, OpeningProof{T: proof.T, S_v: proof.S_v}},
+		{"response outside Zp", "a", C, OpeningProof{T: proof.T, S_v: proof.S_v, S_r: utils.Curve.Params().N}},
+	}
+	for _, test := range tests {
+		if VerifyOpening(testTranscript(test.context), g, h, test.C, test.proof) == nil {
+			t.Errorf("%s: proof accepted", test.name)
+		}
+	}
+
+	// A proof from a wrong opening is rejected
+	forged := ProveOpening(testTranscript("a"), g, h, C, utils.Add_In_P(v, one), r)
+	if VerifyOpening(testTranscript("a"), g, h, C, forged) == nil {
+		t.Error("proof with a wrong opening accepted")
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import "fmt"
+
+// Error of a batch verification, listing the indices of the invalid proofs
+type Batch_Error struct {
+	Kind    string // kind of the proofs, e.g., "range proofs"
+	Invalid []int
+}
+
+func (err *Batch_Error) Error() string {
+	return fmt.Sprintf("invalid %s at indices %v", err.Kind, err.Invalid)
+}
+
+// Verify n proofs with one multi-exponentiation, add(me, i) adding the randomly weighted verification equations of
+// the i-th proof to me, or failing if it is malformed. When the batch fails the invalid proofs are located by bisection
+func Batch_Verify(kind string, n int, add func(me *Multi_Exp, i int) error) error {
+	var index []int
+	for i := 0; i < n; i++ {
+		index = append(index, i)
+	}
+	invalid := bisectVerify(index, add)
+	if len(invalid) > 0 {
+		return &Batch_Error{Kind: kind, Invalid: invalid}
+	}
+	return nil
+}
+
+// Check the proofs in index as one batch and bisect on failure, returning the invalid indices
+func bisectVerify(index []int, add func(me *Multi_Exp, i int) error) []int {
+	if len(index) == 0 || batchCheck(index, add) {
+		return nil
+	}
+	if len(index) == 1 {
+		return index
+	}
+	invalid := bisectVerify(index[:len(index)/2], add)
+	return append(invalid, bisectVerify(index[len(index)/2:], add)...)
+}
+
+// Combine the verification equations of the proofs in index and check the sum is the identity
+func batchCheck(index []int, add func(me *Multi_Exp, i int) error) bool {
+	var me Multi_Exp
+	for _, i := range index {
+		if err := add(&me, i); err != nil {
+			return false
+		}
+	}
+	return Is_Identity_Point(me.Calculate())
+}
@@ -70,6 +70,14 @@ func Cal_Point_Add(vec_a Point, vec_b Point) Point {
 	return result
 }
 
+// First n points of a vector, or the whole vector if it is shorter
+func Point_Prefix(points []Point, n int) []Point {
+	if n < 0 || len(points) < n {
+		return points
+	}
+	return points[:n]
+}
+
 //Calculate the addition of two point vectors
 func Cal_Point_Add_Vec(vec_a []Point, vec_b []Point) []Point {
 	// Create new big int variable: result
@@ -90,3 +98,46 @@ func Is_Equal_Point(a Point, b Point) bool {
 		return false
 	}
 }
+
+// Check whether a point is the point at infinity (0,0)
+func Is_Identity_Point(a Point) bool {
+	return a.X.Sign() == 0 && a.Y.Sign() == 0
+}
+
+// Check whether a point is well formed, i.e., coordinates in the field and lying on the curve
+func Is_Valid_Point(a Point) bool {
+	if a.X == nil || a.Y == nil {
+		return false
+	}
+	if a.X.Sign() < 0 || a.Y.Sign() < 0 || a.X.Cmp(Curve.P) >= 0 || a.Y.Cmp(Curve.P) >= 0 {
+		return false
+	}
+	return Curve.IsOnCurve(a.X, a.Y)
+}
+
+// Standard generator G of the curve
+func Base_Point() Point {
+	return Point{X: new(big.Int).Set(Curve.Gx), Y: new(big.Int).Set(Curve.Gy)}
+}
+
+// Lift a 32-byte x-only public key to the point with even Y (BIP340 lift_x)
+func Lift_X(x []byte) (Point, bool) {
+	if len(x) != 32 {
+		return Point{}, false
+	}
+	px := new(big.Int).SetBytes(x)
+	if px.Cmp(Curve.P) >= 0 {
+		return Point{}, false
+	}
+	y2 := new(big.Int).Exp(px, big.NewInt(3), Curve.P)
+	y2.Add(y2, Curve.B)
+	y2.Mod(y2, Curve.P)
+	py := new(big.Int).ModSqrt(y2, Curve.P)
+	if py == nil {
+		return Point{}, false
+	}
+	if py.Bit(0) == 1 {
+		py.Sub(Curve.P, py)
+	}
+	return Point{X: px, Y: py}, true
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+)
+
+// Fiat-Shamir transcript, every message is absorbed together with its label and
+// each challenge depends on all messages absorbed before it
+type Fiat_Shamir struct {
+	state [32]byte
+}
+
+// Initialize the transcript with a domain separation label
+func (fs *Fiat_Shamir) New(label string) {
+	fs.state = [32]byte{}
+	fs.Append_Bytes("domain", []byte(label))
+}
+
+// Absorb a labelled byte string
+func (fs *Fiat_Shamir) Append_Bytes(label string, data []byte) {
+	var length [8]byte
+	h := sha256.New()
+	h.Write(fs.state[:])
+	binary.BigEndian.PutUint64(length[:], uint64(len(label)))
+	h.Write(length[:])
+	h.Write([]byte(label))
+	binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+	h.Write(length[:])
+	h.Write(data)
+	copy(fs.state[:], h.Sum(nil))
+}
+
+// Absorb a labelled integer
+func (fs *Fiat_Shamir) Append_Int(label string, n int) {
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], uint64(n))
+	fs.Append_Bytes(label, data[:])
+}
+
+// Absorb a labelled scalar as 32 bytes
+func (fs *Fiat_Shamir) Append_Scalar(label string, s *big.Int) {
+	fs.Append_Bytes(label, fixedBytes(s))
+}
+
+// Absorb a labelled point as the 64 bytes X||Y, the point at infinity is all zeros
+func (fs *Fiat_Shamir) Append_Point(label string, p Point) {
+	fs.Append_Bytes(label, append(fixedBytes(p.X), fixedBytes(p.Y)...))
+}
+
+// Absorb a labelled point vector
+func (fs *Fiat_Shamir) Append_Points(label string, points []Point) {
+	fs.Append_Int(label, len(points))
+	for i := range points {
+		fs.Append_Point(label, points[i])
+	}
+}
+
+// Derive a non-zero challenge in Zp and absorb it into the transcript
+func (fs *Fiat_Shamir) Challenge(label string) *big.Int {
+	for {
+		fs.Append_Bytes("challenge", []byte(label))
+		c := new(big.Int).SetBytes(fs.state[:])
+		c.Mod(c, Curve.N)
+		if c.Sign() != 0 {
+			return c
+		}
+	}
+}
+
+// Encode a non-negative integer with 32 bytes, reducing it mod n if it is longer
+func fixedBytes(num *big.Int) []byte {
+	var res [32]byte
+	if num == nil {
+		return res[:]
+	}
+	if num.Sign() < 0 || num.BitLen() > 256 {
+		num = new(big.Int).Mod(num, Curve.N)
+	}
+	num.FillBytes(res[:])
+	return res[:]
+}
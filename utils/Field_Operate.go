@@ -99,3 +99,8 @@ func Sub_In_P(a *big.Int, b *big.Int) *big.Int {
 	c.SetBytes(mbyte[0:32])
 	return c
 }
+
+// Check whether a scalar is well formed, i.e., 0 <= num < n where n is the group order
+func Is_Valid_Scalar(num *big.Int) bool {
+	return num != nil && num.Sign() >= 0 && num.Cmp(Curve.N) < 0
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+)
+
+// Hash a labelled byte string onto the curve by try-and-increment, the discrete logarithm of the result is unknown
+func Hash_To_Point(label string, data []byte) Point {
+	var counter [4]byte
+	for i := uint32(0); ; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h := sha256.New()
+		h.Write([]byte(label))
+		h.Write(data)
+		h.Write(counter[:])
+		x := new(big.Int).SetBytes(h.Sum(nil))
+		if x.Cmp(Curve.P) >= 0 {
+			continue
+		}
+		// y^2 = x^3 + b, take the even root
+		y2 := new(big.Int).Exp(x, big.NewInt(3), Curve.P)
+		y2.Add(y2, Curve.B)
+		y2.Mod(y2, Curve.P)
+		y := new(big.Int).ModSqrt(y2, Curve.P)
+		if y == nil {
+			continue
+		}
+		if y.Bit(0) == 1 {
+			y.Sub(Curve.P, y)
+		}
+		return Point{X: x, Y: y}
+	}
+}
+
+// Hash a point onto the curve, H_p(P)
+func Hash_Point_To_Point(label string, p Point) Point {
+	return Hash_To_Point(label, append(fixedBytes(p.X), fixedBytes(p.Y)...))
+}
@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"math/big"
+)
+
+// Prove P = g^a h^b with <a,b> = c by the logarithmic inner-product argument, returning L, R and the compressed
+// a, b. The challenges are drawn from the transcript fs, which the caller has bound to P: c is absorbed, the
+// generator v is replaced by v^{x_ip} for a challenge x_ip, and each round challenge x_j follows L_j and R_j
+func Opt_Prove(fs *Fiat_Shamir, a []*big.Int, b []*big.Int, G_Vector []Point, H_Vector []Point, G_v Point) ([]Point, []Point, []*big.Int, []*big.Int) {
+	//Parameters
+	var L []Point // store L values in each round
+	var R []Point // store R values in each round
+
+	// Bind the inner product and randomize the generator v
+	fs.Append_Scalar("ip", Cal_IP_Vec(a, b))
+	G_v = Commit(G_v, fs.Challenge("x_ip"))
+
+	l := len(a)
+	var temp, x, inv_x *big.Int
+
+	for l > 1 {
+		// For an odd length the last element is carried to the next round unchanged
+		h := l / 2
+
+		// Write a_L, a_R, b_L, b_R
+		a_L := a[0:h]
+		a_R := a[h : 2*h]
+		b_L := b[0:h]
+		b_R := b[h : 2*h]
+
+		// Write G_L, G_R, H_L, H_R
+		G_L := G_Vector[0:h]
+		G_R := G_Vector[h : 2*h]
+		H_L := H_Vector[0:h]
+		H_R := H_Vector[h : 2*h]
+
+		var G_H, V_ip Point
+		// Calculate L power x
+		temp = Cal_IP_Vec(a_L, b_R)
+		G_H = Pedersen_Commit_Vector(G_R, H_L, a_L, b_R)
+		V_ip = Commit(G_v, temp)
+		L = append(L, Cal_Point_Add(G_H, V_ip))
+
+		// Calculate R power x^-1
+		temp = Cal_IP_Vec(a_R, b_L)
+		G_H = Pedersen_Commit_Vector(G_L, H_R, a_R, b_L)
+		V_ip = Commit(G_v, temp)
+		R = append(R, Cal_Point_Add(G_H, V_ip))
+
+		// Update challenge x with Fiat-Shamir
+		fs.Append_Point("L", L[len(L)-1])
+		fs.Append_Point("R", R[len(R)-1])
+		x = fs.Challenge("x_j")
+		inv_x = Inverse_Zp(x)
+
+		// Calculate compressed vectors a', b' and carry the odd element
+		a_next := Cal_Add_Vec(Cal_Sca_Vec(a_L, x), a_R)
+		b_next := Cal_Add_Vec(Cal_Sca_Vec(b_L, inv_x), b_R)
+
+		// Calculate compressed vectors g', h' and carry the odd generators
+		G_next := Cal_Point_Add_Vec(Cal_Point_Sca_Vec(G_L, inv_x), G_R)
+		H_next := Cal_Point_Add_Vec(Cal_Point_Sca_Vec(H_L, x), H_R)
+
+		if l%2 == 1 {
+			a_next = append(a_next, a[l-1])
+			b_next = append(b_next, b[l-1])
+			G_next = append(G_next, G_Vector[l-1])
+			H_next = append(H_next, H_Vector[l-1])
+		}
+		a, b = a_next, b_next
+		G_Vector, H_Vector = G_next, H_next
+
+		// update length l after each round
+		l = len(a)
+	}
+
+	//return transcript to verify algorithm
+	return L, R, a, b
+}
+
+// Recompute the generator challenge x_ip, the round challenges x, x^{-1} and the s-vectors s, s^{-1} of the
+// inner-product argument of length N for the claimed inner product c, fs should be in the prover's state
+func Opt_Challenges(fs *Fiat_Shamir, c *big.Int, L []Point, R []Point, N int) (*big.Int, []*big.Int, []*big.Int, []*big.Int, []*big.Int, bool) {
+	l := len(L)
+
+	// Check the number of rounds, each round halves the length and carries the odd element
+	var lens []int
+	for n := N; n > 1; n = n/2 + n%2 {
+		lens = append(lens, n)
+	}
+	if len(lens) != l || len(R) != l {
+		return nil, nil, nil, nil, nil, false
+	}
+
+	// Recompute the challenge x_ip and the challenge x of each round with Fiat-Shamir
+	fs.Append_Scalar("ip", c)
+	x_ip := fs.Challenge("x_ip")
+	var x, inv_x []*big.Int
+	for counter := 0; counter < l; counter++ {
+		fs.Append_Point("L", L[counter])
+		fs.Append_Point("R", R[counter])
+		x = append(x, fs.Challenge("x_j"))
+		inv_x = append(inv_x, Inverse_Zp(x[counter]))
+	}
+
+	// Compute s_i = \prod_j x_j^{-1} for every round j where g_i lies in the left half, and s'_i = 1/s_i for h_i,
+	// unrolling the rounds backwards from the final generator
+	s := []*big.Int{big.NewInt(1)}
+	inv_s := []*big.Int{big.NewInt(1)}
+	for counter := l - 1; counter >= 0; counter-- {
+		n := lens[counter]
+		h := n / 2
+		s_prev := make([]*big.Int, n)
+		inv_s_prev := make([]*big.Int, n)
+		for i := 0; i < h; i++ {
+			s_prev[i] = Mul_In_P(s[i], inv_x[counter])
+			s_prev[h+i] = s[i]
+			inv_s_prev[i] = Mul_In_P(inv_s[i], x[counter])
+			inv_s_prev[h+i] = inv_s[i]
+		}
+		if n%2 == 1 {
+			s_prev[n-1] = s[h]
+			inv_s_prev[n-1] = inv_s[h]
+		}
+		s, inv_s = s_prev, inv_s_prev
+	}
+	return x_ip, x, inv_x, s, inv_s, true
+}
+
+// Verify the inner-product argument (L, R, a, b) that P = g^a h^b with <a,b> = c, fs should be in the prover's state
+func Opt_Verify(fs *Fiat_Shamir, L []Point, R []Point, P Point, c *big.Int, G_Vector []Point, H_Vector []Point, G_v Point, a []*big.Int, b []*big.Int) bool {
+	N := len(G_Vector)
+	if len(a) != 1 || len(b) != 1 || len(H_Vector) != N || !Is_Valid_Scalar(c) {
+		return false
+	}
+	x_ip, x, inv_x, s, inv_s, ok := Opt_Challenges(fs, c, L, R, N)
+	if !ok {
+		return false
+	}
+
+	// Check g^{a s} h^{b s'} v^{x_ip (ab - c)} L^{-x} R^{-x^{-1}} = P in one multi-scalar multiplication
+	var points []Point
+	var scalars []*big.Int
+	points = append(points, G_Vector...)
+	scalars = append(scalars, Cal_Sca_Vec(s, a[0])...)
+	points = append(points, H_Vector...)
+	scalars = append(scalars, Cal_Sca_Vec(inv_s, b[0])...)
+	points = append(points, G_v)
+	scalars = append(scalars, Mul_In_P(x_ip, Sub_In_P(Mul_In_P(a[0], b[0]), c)))
+	points = append(points, L...)
+	scalars = append(scalars, Cal_Neg_Vec(x)...)
+	points = append(points, R...)
+	scalars = append(scalars, Cal_Neg_Vec(inv_x)...)
+
+	LHS := Multi_Scalar_Mult(points, scalars)
+	return Is_Equal_Point(LHS, P)
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Transcript of an inner-product argument bound to the commitment P
+func ipaTranscript(P Point) *Fiat_Shamir {
+	var fs Fiat_Shamir
+	fs.New("utils/inner_product")
+	fs.Append_Point("P", P)
+	return &fs
+}
+
+func TestInnerProduct(t *testing.T) {
+	Curve = secp256k1.S256()
+	// Odd lengths carry the last element to the next round, so N = 5 runs ceil(log2 5) = 3 rounds
+	tests := []struct {
+		N      int
+		rounds int
+	}{
+		{1, 0}, {2, 1}, {3, 2}, {4, 2}, {5, 3}, {6, 3}, {7, 3},
+	}
+	for _, test := range tests {
+		N := test.N
+		a := Generate_Random_Zp_Vector(N, 256)
+		b := Generate_Random_Zp_Vector(N, 256)
+		G := GenerateMultiPoint(N)
+		H := GenerateMultiPoint(N)
+		v := GeneratePoint()
+		P := Pedersen_Commit_Vector(G, H, a, b)
+		c := Cal_IP_Vec(a, b)
+
+		L, R, a_c, b_c := Opt_Prove(ipaTranscript(P), a, b, G, H, v)
+		if len(L) != test.rounds || len(R) != test.rounds {
+			t.Errorf("N = %d: %d rounds, want %d", N, len(L), test.rounds)
+			continue
+		}
+		if !Opt_Verify(ipaTranscript(P), L, R, P, c, G, H, v, a_c, b_c) {
+			t.Errorf("N = %d: honest argument rejected", N)
+		}
+		if Opt_Verify(ipaTranscript(P), L, R, P, Add_In_P(c, big.NewInt(1)), G, H, v, a_c, b_c) {
+			t.Errorf("N = %d: argument accepted for a wrong inner product", N)
+		}
+		if Opt_Verify(ipaTranscript(P), L, R, P, c, G, H, v, []*big.Int{Add_In_P(a_c[0], big.NewInt(1))}, b_c) {
+			t.Errorf("N = %d: argument accepted with a wrong final a", N)
+		}
+		if test.rounds > 0 && Opt_Verify(ipaTranscript(P), R, L, P, c, G, H, v, a_c, b_c) {
+			t.Errorf("N = %d: argument accepted with swapped L and R", N)
+		}
+		if test.rounds > 0 && Opt_Verify(ipaTranscript(P), L[1:], R[1:], P, c, G, H, v, a_c, b_c) {
+			t.Errorf("N = %d: argument accepted with a round missing", N)
+		}
+	}
+}
+
+// With round challenges x = H(L + R) a prover fixes S = L + R first and solves L, R = S - L for any commitment.
+// The forged argument satisfies the unbound verification equation but is rejected once x depends on the transcript
+func TestInnerProductForgedRounds(t *testing.T) {
+	Curve = secp256k1.S256()
+	G := GenerateMultiPoint(2)
+	H := GenerateMultiPoint(2)
+	v := GeneratePoint()
+	P := GeneratePoint() // no opening is known
+	c := Generate_Random_Zp(256)
+	T := Cal_Point_Add(P, Commit(v, c))
+	a, b := Generate_Random_Zp(256), Generate_Random_Zp(256)
+
+	// g_1^{a/x} g_2^a h_1^{b x} h_2^b v^{ab} L^{-x} R^{-1/x} = T with R = S - L, so L^{1/x - x} = T K^{-1} S^{1/x}
+	S := GeneratePoint()
+	x32 := sha256.Sum256(S.Point2Bytes())
+	x := new(big.Int).Mod(new(big.Int).SetBytes(x32[:]), Curve.N)
+	inv_x := Inverse_Zp(x)
+	K := Multi_Scalar_Mult([]Point{G[0], G[1], H[0], H[1], v}, []*big.Int{Mul_In_P(a, inv_x), a, Mul_In_P(b, x), b, Mul_In_P(a, b)})
+	Q := Cal_Point_Add(Cal_Point_Add(T, Commit(K, Neg_Zp(big.NewInt(1)))), Commit(S, inv_x))
+	L := Commit(Q, Inverse_Zp(Sub_In_P(inv_x, x)))
+	R := Cal_Point_Add(S, Commit(L, Neg_Zp(big.NewInt(1))))
+
+	unbound := Multi_Scalar_Mult([]Point{G[0], G[1], H[0], H[1], v, L, R},
+		[]*big.Int{Mul_In_P(a, inv_x), a, Mul_In_P(b, x), b, Mul_In_P(a, b), Neg_Zp(x), Neg_Zp(inv_x)})
+	if !Is_Equal_Point(unbound, T) {
+		t.Fatal("the forgery does not satisfy the unbound equation")
+	}
+	if Opt_Verify(ipaTranscript(P), []Point{L}, []Point{R}, P, c, G, H, v, []*big.Int{a}, []*big.Int{b}) {
+		t.Fatal("forged inner-product argument accepted")
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		input string
+		hash  string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{"The quick brown fox jumps over the lazy dog", "4d741b6f1eb29cb2a9b9911c82f56fa8d73b04959d3d9d222895df6c0b28aa15"},
+	}
+	for _, test := range tests {
+		hash := Keccak256([]byte(test.input))
+		if hex.EncodeToString(hash[:]) != test.hash {
+			t.Errorf("Keccak256(%q) = %x, want %s", test.input, hash, test.hash)
+		}
+	}
+
+	// Split inputs hash as their concatenation, across the 136-byte block boundary
+	msg := bytes.Repeat([]byte{0xa5}, 300)
+	for _, cut := range []int{0, 135, 136, 137, 300} {
+		if Keccak256(msg[:cut], msg[cut:]) != Keccak256(msg) {
+			t.Errorf("split at %d changes the hash", cut)
+		}
+	}
+	if Keccak256(msg[:135]) == Keccak256(msg[:136]) {
+		t.Error("inputs of 135 and 136 bytes collide")
+	}
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// Convert an affine point into Jacobian coordinates, the point at infinity is kept as (0,0,0)
+func point2Jacobian(p Point, result *secp256k1.JacobianPoint) {
+	if p.X.Sign() == 0 && p.Y.Sign() == 0 {
+		result.X.SetInt(0)
+		result.Y.SetInt(0)
+		result.Z.SetInt(0)
+		return
+	}
+	result.X.SetByteSlice(p.X.Bytes())
+	result.Y.SetByteSlice(p.Y.Bytes())
+	result.Z.SetInt(1)
+}
+
+// Convert a Jacobian point back into affine coordinates, the point at infinity becomes (0,0)
+func jacobian2Point(p *secp256k1.JacobianPoint) Point {
+	var result Point
+	if p.Z.IsZero() || (p.X.IsZero() && p.Y.IsZero()) {
+		result.X = big.NewInt(0)
+		result.Y = big.NewInt(0)
+		return result
+	}
+	p.ToAffine()
+	result.X = new(big.Int).SetBytes(p.X.Bytes()[:])
+	result.Y = new(big.Int).SetBytes(p.Y.Bytes()[:])
+	return result
+}
+
+// Add the Jacobian point p to acc in place
+func addJacobian(acc *secp256k1.JacobianPoint, p *secp256k1.JacobianPoint) {
+	var temp secp256k1.JacobianPoint
+	secp256k1.AddNonConst(acc, p, &temp)
+	acc.Set(&temp)
+}
+
+// Choose the window width of the bucket method according to the number of terms
+func msmWindow(n int) int {
+	switch {
+	case n < 8:
+		return 2
+	case n < 32:
+		return 3
+	case n < 128:
+		return 4
+	case n < 512:
+		return 5
+	case n < 2048:
+		return 6
+	case n < 8192:
+		return 7
+	default:
+		return 8
+	}
+}
+
+// Calculate the multi-scalar multiplication \sum scalars_i * points_i with one bucket (Pippenger) pass
+func Multi_Scalar_Mult(points []Point, scalars []*big.Int) Point {
+	n := len(points)
+	if n == 0 {
+		return Point{X: big.NewInt(0), Y: big.NewInt(0)}
+	}
+
+	// Reduce scalars into Zp and convert points into Jacobian coordinates
+	jac := make([]secp256k1.JacobianPoint, n)
+	sca := make([][32]byte, n)
+	for i := 0; i < n; i++ {
+		point2Jacobian(points[i], &jac[i])
+		var s secp256k1.ModNScalar
+		s.SetByteSlice(scalars[i].Bytes())
+		sca[i] = s.Bytes()
+	}
+
+	c := msmWindow(n)
+	windows := (256 + c - 1) / c
+	buckets := make([]secp256k1.JacobianPoint, 1<<uint(c))
+	var result, sum, running secp256k1.JacobianPoint
+
+	for w := windows - 1; w >= 0; w-- {
+		// Shift the accumulated result by the window width
+		for i := 0; i < c; i++ {
+			var temp secp256k1.JacobianPoint
+			secp256k1.DoubleNonConst(&result, &temp)
+			result.Set(&temp)
+		}
+
+		// Put every point into the bucket indexed by its current window of the scalar
+		for i := range buckets {
+			buckets[i] = secp256k1.JacobianPoint{}
+		}
+		for i := 0; i < n; i++ {
+			digit := scalarWindow(&sca[i], w*c, c)
+			if digit != 0 {
+				addJacobian(&buckets[digit], &jac[i])
+			}
+		}
+
+		// Sum_j j*bucket_j with running sums
+		running = secp256k1.JacobianPoint{}
+		sum = secp256k1.JacobianPoint{}
+		for j := len(buckets) - 1; j > 0; j-- {
+			addJacobian(&running, &buckets[j])
+			addJacobian(&sum, &running)
+		}
+		addJacobian(&result, &sum)
+	}
+
+	return jacobian2Point(&result)
+}
+
+// Extract c bits of a big-endian 256-bit scalar starting from bit position offset
+func scalarWindow(s *[32]byte, offset int, c int) int {
+	digit := 0
+	for i := c - 1; i >= 0; i-- {
+		bit := offset + i
+		if bit >= 256 {
+			continue
+		}
+		b := (s[31-bit/8] >> uint(bit%8)) & 1
+		digit = digit<<1 | int(b)
+	}
+	return digit
+}
+
+// Terms of a multi-scalar multiplication, the scalars of repeated points are merged so that
+// generators shared by several equations are only multiplied once
+type Multi_Exp struct {
+	points  []Point
+	scalars []*big.Int
+	index   map[string]int
+}
+
+// Add the term scalar*point
+func (me *Multi_Exp) Add_Term(point Point, scalar *big.Int) {
+	if me.index == nil {
+		me.index = make(map[string]int)
+	}
+	var key [64]byte
+	point.X.FillBytes(key[:32])
+	point.Y.FillBytes(key[32:])
+	if i, ok := me.index[string(key[:])]; ok {
+		me.scalars[i] = Add_In_P(me.scalars[i], scalar)
+		return
+	}
+	me.index[string(key[:])] = len(me.points)
+	me.points = append(me.points, point)
+	me.scalars = append(me.scalars, scalar)
+}
+
+// Add the terms scalars_i*points_i
+func (me *Multi_Exp) Add_Terms(points []Point, scalars []*big.Int) {
+	for i := range points {
+		me.Add_Term(points[i], scalars[i])
+	}
+}
+
+// Calculate the sum of all terms
+func (me *Multi_Exp) Calculate() Point {
+	return Multi_Scalar_Mult(me.points, me.scalars)
+}
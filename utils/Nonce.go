@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+)
+
+// Deterministic source of prover nonces in the style of RFC 6979 and BIP340: every nonce is a hash of the secret
+// witness, the statement transcript and optional extra randomness, so the same inputs always give the same proof
+// and a broken random number generator cannot leak the secrets. A nil source draws the nonces from crypto/rand
+type Nonce_Source struct {
+	aux     [32]byte // hash of the extra randomness
+	key     [32]byte // nonce key, set by Bind
+	bound   bool
+	counter uint64
+}
+
+// Create a nonce source with optional extra randomness aux, which may be nil
+func New_Nonce_Source(aux []byte) *Nonce_Source {
+	var ns Nonce_Source
+	ns.aux = taggedHash("nonce/aux", aux)
+	return &ns
+}
+
+// Create a hedged nonce source bound to the statement absorbed by fs and the secret witness, with fresh randomness
+// as aux. Its nonces differ between runs but stay unpredictable when crypto/rand alone is weak
+func Hedged_Nonce_Source(fs Fiat_Shamir, secrets []*big.Int) *Nonce_Source {
+	ns := New_Nonce_Source(fixedBytes(Generate_Random_Zp(256)))
+	ns.Bind(fs, secrets)
+	return ns
+}
+
+// Bind the source to the statement absorbed by the transcript fs and the secret witness, restarting the nonce sequence
+func (ns *Nonce_Source) Bind(fs Fiat_Shamir, secrets []*big.Int) {
+	if ns == nil {
+		return
+	}
+	var witness []byte
+	for i := range secrets {
+		witness = append(witness, fixedBytes(secrets[i])...)
+	}
+	masked := taggedHash("nonce/witness", witness)
+	for i := range masked {
+		masked[i] ^= ns.aux[i]
+	}
+	ns.key = taggedHash("nonce/key", masked[:], fs.state[:])
+	ns.bound = true
+	ns.counter = 0
+}
+
+// Derive the next non-zero nonce in Zp
+func (ns *Nonce_Source) Scalar() *big.Int {
+	if ns == nil || !ns.bound {
+		for {
+			c := Add_In_P(Generate_Random_Zp(256), big.NewInt(0))
+			if c.Sign() != 0 {
+				return c
+			}
+		}
+	}
+	for {
+		var counter [8]byte
+		binary.BigEndian.PutUint64(counter[:], ns.counter)
+		ns.counter++
+		h := taggedHash("nonce/scalar", ns.key[:], counter[:])
+		c := new(big.Int).SetBytes(h[:])
+		// Reject instead of reducing so the nonces are uniform
+		if c.Sign() != 0 && c.Cmp(Curve.N) < 0 {
+			return c
+		}
+	}
+}
+
+// Derive the next n nonces
+func (ns *Nonce_Source) Scalar_Vector(n int) []*big.Int {
+	var res []*big.Int
+	for i := 0; i < n; i++ {
+		res = append(res, ns.Scalar())
+	}
+	return res
+}
+
+////////////////////////Private functions
+
+// BIP340 tagged hash SHA256(SHA256(tag) || SHA256(tag) || data)
+func taggedHash(tag string, data ...[]byte) [32]byte {
+	t := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(t[:])
+	h.Write(t[:])
+	for i := range data {
+		h.Write(data[i])
+	}
+	var res [32]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}